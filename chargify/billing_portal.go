@@ -0,0 +1,224 @@
+package chargify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ManagementLink is a link logging a customer into the billing portal.
+type ManagementLink struct {
+	Url                  string         `json:"url,omitempty"`
+	FetchCount           int            `json:"fetch_count,omitempty"`
+	CreatedAt            *FormattedTime `json:"created_at,omitempty"`
+	NewLinkAvailableAt   *FormattedTime `json:"new_link_available_at,omitempty"`
+	ExpiresAt            *FormattedTime `json:"expires_at,omitempty"`
+	LastInviteSentAt     *FormattedTime `json:"last_invite_sent_at,omitempty"`
+	LastInviteAcceptedAt *FormattedTime `json:"last_invite_accepted_at,omitempty"`
+	LastInviteRevokedAt  *FormattedTime `json:"last_invite_revoked_at,omitempty"`
+}
+
+// valid reports whether the link can still be used at now.
+func (l *ManagementLink) valid(now time.Time) bool {
+	return l.ExpiresAt != nil && l.ExpiresAt.Time != nil && now.Before(*l.ExpiresAt.Time)
+}
+
+// PortalInvitation is the state of a customer's invitation to the billing
+// portal.
+type PortalInvitation struct {
+	LastSentAt         *FormattedTime `json:"last_sent_at,omitempty"`
+	LastAcceptedAt     *FormattedTime `json:"last_accepted_at,omitempty"`
+	SendInviteLinkText string         `json:"send_invite_link_text,omitempty"`
+	UninvitedCount     int            `json:"uninvited_count,omitempty"`
+
+	// Customer is a copy of the customer invited, with its invitation
+	// times updated to match. It is set by ResendInvitation.
+	Customer *Customer `json:"-"`
+}
+
+// BillingPortalService handles the access of customers to the billing portal,
+// where they manage their own subscriptions and payment details.
+type BillingPortalService service
+
+// Enable gives a customer access to the billing portal and, if invite is set,
+// emails them an invitation to it. RevokeAccess disables it again.
+//
+// Chargify API docs: https://reference.chargify.com/v1/billing-portal/enable-billing-portal-for-customer
+func (s *BillingPortalService) Enable(ctx context.Context, customerID int, invite bool) (*Customer, *Response, error) {
+	ctx = withOperationName(ctx, "BillingPortal.Enable")
+
+	u := fmt.Sprintf("portal/customers/%d/enable", customerID)
+	if invite {
+		u += "?auto_invite=1"
+	}
+	req, err := s.client.NewRequest("POST", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cw := new(CustomerWrapper)
+	resp, err := s.client.Do(ctx, req, cw)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return cw.Customer, resp, nil
+}
+
+// ManagementLink fetches a link logging a customer into the billing portal.
+//
+// Chargify limits how often a new link can be generated, so the client keeps
+// the last link of every customer and returns it until it expires, with a
+// *Response whose Cached field is set.
+//
+// Chargify API docs: https://reference.chargify.com/v1/billing-portal/read-billing-portal-management-link
+func (s *BillingPortalService) ManagementLink(ctx context.Context, customerID int) (*ManagementLink, *Response, error) {
+	ctx = withOperationName(ctx, "BillingPortal.ManagementLink")
+
+	if link := s.client.portalLinks.get(customerID); link != nil {
+		return link, cachedResponse(), nil
+	}
+
+	u := fmt.Sprintf("portal/customers/%d/management_link", customerID)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	link := new(ManagementLink)
+	resp, err := s.client.Do(ctx, req, link)
+	if err != nil {
+		return nil, resp, err
+	}
+	s.client.portalLinks.set(customerID, link)
+
+	return link, resp, nil
+}
+
+// ResendInvitation emails customer a new invitation to the billing portal.
+// The Customer of the returned invitation is a copy of customer with its
+// PortalInviteLastSentAt and PortalInviteLastAcceptedAt fields updated;
+// customer itself is left unchanged.
+//
+// Chargify API docs: https://reference.chargify.com/v1/billing-portal/resend-invitation
+func (s *BillingPortalService) ResendInvitation(ctx context.Context, customer *Customer) (*PortalInvitation, *Response, error) {
+	ctx = withOperationName(ctx, "BillingPortal.ResendInvitation")
+
+	u := fmt.Sprintf("portal/customers/%d/invitations/invite", customer.Id)
+	req, err := s.client.NewRequest("POST", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	inv := new(PortalInvitation)
+	resp, err := s.client.Do(ctx, req, inv)
+	if err != nil {
+		return nil, resp, err
+	}
+	updated := *customer
+	updated.PortalInviteLastSentAt = inv.LastSentAt
+	updated.PortalInviteLastAcceptedAt = inv.LastAcceptedAt
+	inv.Customer = &updated
+
+	return inv, resp, nil
+}
+
+// RevokeAccess removes a customer's access to the billing portal, and drops
+// the management link kept for them.
+//
+// Chargify API docs: https://reference.chargify.com/v1/billing-portal/revoke-access
+func (s *BillingPortalService) RevokeAccess(ctx context.Context, customerID int) (*Response, error) {
+	ctx = withOperationName(ctx, "BillingPortal.RevokeAccess")
+
+	u := fmt.Sprintf("portal/customers/%d/invitations/revoke", customerID)
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+	if err == nil {
+		s.client.portalLinks.forget(customerID)
+	}
+	return resp, err
+}
+
+// cachedResponse returns the response of a call answered by the client
+// without calling the API.
+func cachedResponse() *Response {
+	return &Response{
+		Response: &http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     make(http.Header),
+			Body:       http.NoBody,
+		},
+		Cached: true,
+	}
+}
+
+// portalLinkSweepInterval is how often portalLinks drops expired links.
+const portalLinkSweepInterval = time.Minute
+
+// portalLinks keeps the last management link of customers by id.
+type portalLinks struct {
+	mu        sync.Mutex
+	links     map[int]*ManagementLink
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+func newPortalLinks() *portalLinks {
+	return &portalLinks{links: make(map[int]*ManagementLink), now: time.Now, lastSweep: time.Now()}
+}
+
+// get returns a copy of the link of a customer, or nil if there is none
+// that is still valid.
+func (p *portalLinks) get(customerID int) *ManagementLink {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	link, ok := p.links[customerID]
+	if !ok {
+		return nil
+	}
+	if !link.valid(p.now()) {
+		delete(p.links, customerID)
+		return nil
+	}
+	l := *link
+	return &l
+}
+
+// set keeps a copy of the link of a customer if it is valid.
+func (p *portalLinks) set(customerID int, link *ManagementLink) {
+	now := p.now()
+	if !link.valid(now) {
+		return
+	}
+	l := *link
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.links[customerID] = &l
+
+	// Drop expired links now and then, so that links of customers who do
+	// not ask for one again are not kept forever.
+	if now.Sub(p.lastSweep) >= portalLinkSweepInterval {
+		for id, link := range p.links {
+			if !link.valid(now) {
+				delete(p.links, id)
+			}
+		}
+		p.lastSweep = now
+	}
+}
+
+func (p *portalLinks) forget(customerID int) {
+	p.mu.Lock()
+	delete(p.links, customerID)
+	p.mu.Unlock()
+}
@@ -0,0 +1,135 @@
+package chargify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBillingPortalService_Enable(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/portal/customers/14399371/enable", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		if got := r.URL.Query().Get("auto_invite"); got != "1" {
+			t.Errorf("BillingPortal.Enable auto_invite is %q, want 1", got)
+		}
+		fmt.Fprint(w, `{"customer": {"id":14399371}}`)
+	})
+
+	customer, _, err := client.BillingPortal.Enable(context.Background(), 14399371, true)
+	if err != nil {
+		t.Errorf("BillingPortal.Enable returned error: %v", err)
+	}
+
+	want := &Customer{Id: 14399371}
+	if !reflect.DeepEqual(customer, want) {
+		t.Errorf("BillingPortal.Enable returned %+v, want %+v", customer, want)
+	}
+}
+
+func TestBillingPortalService_ManagementLink(t *testing.T) {
+	setup()
+	defer teardown()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	client.portalLinks.now = func() time.Time { return now }
+
+	requests := 0
+	mux.HandleFunc("/portal/customers/14399371/management_link", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		requests++
+		fmt.Fprintf(w, `{"url":"https://portal.example.com/%d","fetch_count":%d,"expires_at":"2024-01-01T13:00:00+00:00"}`, requests, requests)
+	})
+	mux.HandleFunc("/portal/customers/14399371/invitations/revoke", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+	})
+
+	link, resp, err := client.BillingPortal.ManagementLink(context.Background(), 14399371)
+	if err != nil {
+		t.Fatalf("BillingPortal.ManagementLink returned error: %v", err)
+	}
+	if link.Url != "https://portal.example.com/1" || resp == nil || resp.Cached {
+		t.Errorf("BillingPortal.ManagementLink returned %+v, want the first link from the API", link)
+	}
+
+	// The link is reused until it expires.
+	now = now.Add(59 * time.Minute)
+	link, resp, _ = client.BillingPortal.ManagementLink(context.Background(), 14399371)
+	if link.Url != "https://portal.example.com/1" || requests != 1 {
+		t.Errorf("BillingPortal.ManagementLink returned %+v after %d requests, want the cached link", link, requests)
+	}
+	if resp == nil || !resp.Cached || resp.StatusCode != http.StatusOK {
+		t.Errorf("BillingPortal.ManagementLink returned response %+v for the cached link, want a cached 200", resp)
+	}
+
+	now = now.Add(time.Minute)
+	link, _, _ = client.BillingPortal.ManagementLink(context.Background(), 14399371)
+	if link.Url != "https://portal.example.com/2" {
+		t.Errorf("BillingPortal.ManagementLink returned %+v after expiry, want a new link", link)
+	}
+
+	// Revoking access forgets the link.
+	now = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	if _, err := client.BillingPortal.RevokeAccess(context.Background(), 14399371); err != nil {
+		t.Fatalf("BillingPortal.RevokeAccess returned error: %v", err)
+	}
+	client.BillingPortal.ManagementLink(context.Background(), 14399371)
+	if requests != 3 {
+		t.Errorf("BillingPortal.ManagementLink sent %d requests, want 3 after revoking access", requests)
+	}
+}
+
+func TestBillingPortalService_ResendInvitation(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/portal/customers/14399371/invitations/invite", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		fmt.Fprint(w, `{"last_sent_at":"2024-01-02T10:00:00-05:00","last_accepted_at":"2023-06-01T09:00:00-05:00","uninvited_count":1}`)
+	})
+
+	customer := &Customer{Id: 14399371}
+	inv, _, err := client.BillingPortal.ResendInvitation(context.Background(), customer)
+	if err != nil {
+		t.Fatalf("BillingPortal.ResendInvitation returned error: %v", err)
+	}
+
+	want := &PortalInvitation{
+		LastSentAt:     NewFormattedTime(`"2024-01-02T10:00:00-05:00"`),
+		LastAcceptedAt: NewFormattedTime(`"2023-06-01T09:00:00-05:00"`),
+		UninvitedCount: 1,
+		Customer:       inv.Customer,
+	}
+	if !reflect.DeepEqual(inv, want) {
+		t.Errorf("BillingPortal.ResendInvitation returned %+v, want %+v", inv, want)
+	}
+	if customer.PortalInviteLastSentAt != nil || customer.PortalInviteLastAcceptedAt != nil {
+		t.Errorf("BillingPortal.ResendInvitation modified the customer passed in: %+v", customer)
+	}
+	updated := inv.Customer
+	if updated == nil || updated.Id != customer.Id || updated.PortalInviteLastSentAt != inv.LastSentAt || updated.PortalInviteLastAcceptedAt != inv.LastAcceptedAt {
+		t.Errorf("BillingPortal.ResendInvitation returned customer %+v, want a copy with the invite times", updated)
+	}
+}
+
+func TestPortalLinks_sweep(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	links := newPortalLinks()
+	links.now = func() time.Time { return now }
+	links.lastSweep = now
+
+	expiry := NewFormattedTime(`"2024-01-01T12:30:00+00:00"`)
+	for id := 1; id <= 3; id++ {
+		links.set(id, &ManagementLink{Url: "https://portal.example.com", ExpiresAt: expiry})
+	}
+
+	now = now.Add(time.Hour)
+	links.set(4, &ManagementLink{Url: "https://portal.example.com", ExpiresAt: NewFormattedTime(`"2024-01-01T14:00:00+00:00"`)})
+	if len(links.links) != 1 || links.links[4] == nil {
+		t.Errorf("portalLinks holds %d links, want only the valid link of customer 4", len(links.links))
+	}
+}
@@ -0,0 +1,309 @@
+// Package bulk applies an operation to many Chargify subscriptions at once,
+// e.g. to move every subscriber of a product to its new price.
+//
+//	runner := &bulk.Runner{Client: client, Workers: 8, Checkpoint: "migrate-gold.jsonl"}
+//	report, err := runner.Run(ctx, bulk.Migrate(&chargify.Migration{ProductHandle: "gold"}), ids)
+//
+// Progress is checkpointed to a file as subscriptions are done, so a run that
+// is interrupted can be resumed by running it again with the same checkpoint:
+// subscriptions the operation already succeeded for are skipped.
+package bulk
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/m0dd3r/go-chargify/chargify"
+)
+
+const defaultWorkers = 4
+
+// An Operation is a change applied to subscriptions one at a time.
+type Operation struct {
+	// Name describes the operation in reports and checkpoints, e.g.
+	// "migrate to gold (b0869a3b)". Checkpoints only mark subscriptions
+	// done for the operation of the same name, so it must identify all of
+	// its parameters; the operations of this package end their name with a
+	// hash of them.
+	Name string
+
+	// Apply changes the subscription with the given id.
+	Apply func(ctx context.Context, client *chargify.Client, id int) error
+}
+
+// Migrate returns an operation moving subscriptions to another product.
+func Migrate(migration *chargify.Migration) Operation {
+	to := migration.ProductHandle
+	if to == "" {
+		to = fmt.Sprintf("product %d", migration.ProductId)
+	}
+	return Operation{
+		Name: fmt.Sprintf("migrate to %s (%s)", to, paramsHash(migration)),
+		Apply: func(ctx context.Context, client *chargify.Client, id int) error {
+			_, _, err := client.Subscriptions.Migrate(ctx, id, migration)
+			return err
+		},
+	}
+}
+
+// CancelAtPeriodEnd returns an operation scheduling subscriptions to be
+// canceled at the end of their current billing period.
+func CancelAtPeriodEnd() Operation {
+	return Operation{
+		Name: "cancel at period end",
+		Apply: func(ctx context.Context, client *chargify.Client, id int) error {
+			_, err := client.Subscriptions.DelayedCancel(ctx, id)
+			return err
+		},
+	}
+}
+
+// AddCoupon returns an operation applying a coupon to subscriptions.
+func AddCoupon(code string) Operation {
+	return Operation{
+		Name: "add coupon " + code,
+		Apply: func(ctx context.Context, client *chargify.Client, id int) error {
+			_, _, err := client.Subscriptions.AddCoupon(ctx, id, code)
+			return err
+		},
+	}
+}
+
+// UpdateMetadata returns an operation setting custom field values on
+// subscriptions, keyed by field name.
+func UpdateMetadata(values map[string]string) Operation {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	metadata := make([]*chargify.Metadata, len(names))
+	for i, name := range names {
+		metadata[i] = &chargify.Metadata{Name: name, Value: values[name]}
+	}
+
+	return Operation{
+		Name: fmt.Sprintf("update metadata %v (%s)", names, paramsHash(metadata)),
+		Apply: func(ctx context.Context, client *chargify.Client, id int) error {
+			_, _, err := client.CustomFields.UpsertMetadata(ctx, chargify.ResourceSubscriptions, id, metadata)
+			return err
+		},
+	}
+}
+
+// paramsHash returns a short hash of the parameters of an operation, for its
+// name to tell apart operations that differ in more than what the rest of the
+// name shows, such as the values set or the options of a migration.
+func paramsHash(params interface{}) string {
+	b, err := json.Marshal(params)
+	if err != nil {
+		panic(err) // parameters are plain API structs
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:4])
+}
+
+// Status is the outcome of an operation for one subscription.
+type Status string
+
+const (
+	Succeeded Status = "succeeded"
+	Failed    Status = "failed"
+
+	// Skipped subscriptions were already done according to the checkpoint.
+	Skipped Status = "skipped"
+
+	// Checked subscriptions were found to exist by a dry run.
+	Checked Status = "checked"
+)
+
+// Result is the outcome of an operation for one subscription.
+type Result struct {
+	SubscriptionId int           `json:"subscription_id"`
+	Status         Status        `json:"status"`
+	Error          string        `json:"error,omitempty"`
+	Duration       time.Duration `json:"duration,omitempty"`
+}
+
+// Runner applies operations to subscriptions.
+type Runner struct {
+	Client *chargify.Client
+
+	// Workers is how many subscriptions are worked on concurrently.
+	// Default: 4. The client's rate limit, if any, applies across workers.
+	Workers int
+
+	// DryRun makes the runner fetch each subscription instead of changing
+	// it, to check that the run would find them all. Dry runs do not use
+	// the checkpoint.
+	DryRun bool
+
+	// Checkpoint is the path of the file progress is saved to. If empty,
+	// progress is not saved.
+	Checkpoint string
+
+	// Progress, if set, is called after each subscription is done with the
+	// number of subscriptions done so far, out of total. Calls are not
+	// concurrent.
+	Progress func(done, total int, result Result)
+}
+
+// Report summarizes a run.
+type Report struct {
+	Operation string    `json:"operation"`
+	DryRun    bool      `json:"dry_run,omitempty"`
+	Started   time.Time `json:"started"`
+	Finished  time.Time `json:"finished"`
+
+	// Results holds one result per subscription done, in the order the
+	// subscriptions were given. Subscriptions not done because the run was
+	// canceled have no result.
+	Results []Result `json:"results"`
+
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped"`
+	Checked   int `json:"checked"`
+}
+
+// Errors returns the results of the subscriptions the operation failed for.
+func (r *Report) Errors() []Result {
+	var failed []Result
+	for _, res := range r.Results {
+		if res.Status == Failed {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// Run applies op to the subscriptions with the given ids. Failing
+// subscriptions do not stop the run; they are listed in the report. The
+// returned error is only set if the checkpoint cannot be used or ctx ends
+// before the run does, in which case the report covers the subscriptions
+// done so far.
+func (r *Runner) Run(ctx context.Context, op Operation, ids []int) (*Report, error) {
+	report := &Report{Operation: op.Name, DryRun: r.DryRun, Started: time.Now()}
+
+	var cp *checkpoint
+	if r.Checkpoint != "" && !r.DryRun {
+		var err error
+		if cp, err = openCheckpoint(r.Checkpoint, op.Name); err != nil {
+			return nil, err
+		}
+		defer cp.Close()
+	}
+
+	workers := r.Workers
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+
+	type job struct {
+		index int
+		id    int
+	}
+	type done struct {
+		index  int
+		result Result
+	}
+	jobs := make(chan job)
+	results := make(chan done)
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for j := range jobs {
+				results <- done{j.index, r.apply(ctx, op, j.id)}
+			}
+		}()
+	}
+
+	byIndex := make(map[int]Result, len(ids))
+	var pending []job
+	for i, id := range ids {
+		if cp != nil && cp.done(id) {
+			byIndex[i] = Result{SubscriptionId: id, Status: Skipped}
+			continue
+		}
+		pending = append(pending, job{i, id})
+	}
+
+	var cpErr error
+	finished := len(byIndex)
+	record := func(d done) {
+		byIndex[d.index] = d.result
+		finished++
+		if cp != nil && cpErr == nil {
+			cpErr = cp.save(d.result)
+		}
+		if r.Progress != nil {
+			r.Progress(finished, len(ids), d.result)
+		}
+	}
+
+	inFlight := 0
+	for len(pending) > 0 && ctx.Err() == nil && cpErr == nil {
+		select {
+		case jobs <- pending[0]:
+			pending = pending[1:]
+			inFlight++
+		case d := <-results:
+			inFlight--
+			record(d)
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+	for ; inFlight > 0; inFlight-- {
+		record(<-results)
+	}
+
+	for i := range ids {
+		res, ok := byIndex[i]
+		if !ok {
+			continue
+		}
+		report.Results = append(report.Results, res)
+		switch res.Status {
+		case Succeeded:
+			report.Succeeded++
+		case Failed:
+			report.Failed++
+		case Skipped:
+			report.Skipped++
+		case Checked:
+			report.Checked++
+		}
+	}
+	report.Finished = time.Now()
+
+	if cpErr != nil {
+		return report, cpErr
+	}
+	return report, ctx.Err()
+}
+
+// apply applies op to one subscription, or checks it exists on dry runs.
+func (r *Runner) apply(ctx context.Context, op Operation, id int) Result {
+	start := time.Now()
+	var err error
+	status := Succeeded
+	if r.DryRun {
+		_, _, err = r.Client.Subscriptions.Get(ctx, id)
+		status = Checked
+	} else {
+		err = op.Apply(ctx, r.Client, id)
+	}
+
+	res := Result{SubscriptionId: id, Status: status, Duration: time.Since(start)}
+	if err != nil {
+		res.Status = Failed
+		res.Error = err.Error()
+	}
+	return res
+}
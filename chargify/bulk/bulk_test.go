@@ -0,0 +1,220 @@
+package bulk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/m0dd3r/go-chargify/chargify"
+)
+
+// server records the requests it receives and fails those for subscriptions
+// in fail.
+type server struct {
+	mu       sync.Mutex
+	requests []string
+	fail     map[int]bool
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var id int
+	fmt.Sscanf(r.URL.Path, "/subscriptions/%d", &id)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.RequestURI())
+	fail := s.fail[id]
+	s.mu.Unlock()
+
+	if fail {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `{"errors": ["Subscription cannot be migrated"]}`)
+		return
+	}
+	fmt.Fprintf(w, `{"subscription": {"id":%d}}`, id)
+}
+
+func setup(t *testing.T, fail ...int) (*chargify.Client, *server) {
+	s := &server{fail: make(map[int]bool)}
+	for _, id := range fail {
+		s.fail[id] = true
+	}
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+
+	client, err := chargify.New("", "", chargify.WithBaseURL(ts.URL))
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	return client, s
+}
+
+func statuses(report *Report) map[int]Status {
+	m := make(map[int]Status)
+	for _, res := range report.Results {
+		m[res.SubscriptionId] = res.Status
+	}
+	return m
+}
+
+func TestRunner_Run(t *testing.T) {
+	client, srv := setup(t, 3)
+
+	var progress []int
+	runner := &Runner{
+		Client:  client,
+		Workers: 3,
+		Progress: func(done, total int, result Result) {
+			if total != 5 {
+				t.Errorf("Progress total is %d, want 5", total)
+			}
+			progress = append(progress, done)
+		},
+	}
+	report, err := runner.Run(context.Background(), AddCoupon("SAVE10"), []int{1, 2, 3, 4, 5})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	if report.Operation != "add coupon SAVE10" || report.Succeeded != 4 || report.Failed != 1 {
+		t.Errorf("Run returned report %+v, want 4 succeeded and 1 failed", report)
+	}
+	var ids []int
+	for _, res := range report.Results {
+		ids = append(ids, res.SubscriptionId)
+	}
+	if want := []int{1, 2, 3, 4, 5}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Report results are for subscriptions %v, want %v", ids, want)
+	}
+	if errs := report.Errors(); len(errs) != 1 || errs[0].SubscriptionId != 3 || !strings.Contains(errs[0].Error, "cannot be migrated") {
+		t.Errorf("Report errors are %+v, want the failure of subscription 3", errs)
+	}
+	if want := []int{1, 2, 3, 4, 5}; !reflect.DeepEqual(progress, want) {
+		t.Errorf("Progress saw %v done, want %v", progress, want)
+	}
+	if len(srv.requests) != 5 || !strings.HasPrefix(srv.requests[0], "POST /subscriptions/") {
+		t.Errorf("Server received %q, want 5 coupon requests", srv.requests)
+	}
+}
+
+func TestRunner_Run_checkpoint(t *testing.T) {
+	client, srv := setup(t, 2)
+	path := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+	runner := &Runner{Client: client, Checkpoint: path}
+	op := Migrate(&chargify.Migration{ProductHandle: "gold"})
+
+	if _, err := runner.Run(context.Background(), op, []int{1, 2, 3}); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	// Simulate a crash while writing the checkpoint.
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	f.WriteString(`{"operation":"` + op.Name + `","subscr`)
+	f.Close()
+
+	srv.fail = nil
+	srv.requests = nil
+	report, err := runner.Run(context.Background(), op, []int{1, 2, 3, 4})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	want := map[int]Status{1: Skipped, 2: Succeeded, 3: Skipped, 4: Succeeded}
+	if got := statuses(report); !reflect.DeepEqual(got, want) {
+		t.Errorf("Resumed run statuses are %v, want %v", got, want)
+	}
+	if len(srv.requests) != 2 {
+		t.Errorf("Resumed run sent %q, want requests for subscriptions 2 and 4 only", srv.requests)
+	}
+
+	// Another operation does not reuse the checkpoint of this one.
+	report, _ = runner.Run(context.Background(), CancelAtPeriodEnd(), []int{1})
+	if report.Succeeded != 1 {
+		t.Errorf("Run of another operation returned %+v, want subscription 1 done again", report)
+	}
+
+	b, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != 7 {
+		t.Fatalf("Checkpoint has %d lines, want 7", len(lines))
+	}
+	var last checkpointLine
+	if err := json.Unmarshal([]byte(lines[6]), &last); err != nil || last.Operation != "cancel at period end" {
+		t.Errorf("Last checkpoint line is %q, want the cancel of subscription 1", lines[6])
+	}
+}
+
+func TestOperation_names(t *testing.T) {
+	names := map[string]bool{}
+	for _, op := range []Operation{
+		Migrate(&chargify.Migration{ProductHandle: "gold"}),
+		Migrate(&chargify.Migration{ProductHandle: "gold", IncludeCoupons: true}),
+		Migrate(&chargify.Migration{ProductHandle: "gold", ProductPricePointId: 7}),
+		UpdateMetadata(map[string]string{"tier": "gold"}),
+		UpdateMetadata(map[string]string{"tier": "silver"}),
+	} {
+		if names[op.Name] {
+			t.Errorf("Operation name %q is not unique", op.Name)
+		}
+		names[op.Name] = true
+	}
+
+	a := UpdateMetadata(map[string]string{"tier": "gold", "region": "eu"})
+	b := UpdateMetadata(map[string]string{"region": "eu", "tier": "gold"})
+	if a.Name != b.Name {
+		t.Errorf("Equal operations are named %q and %q, want the same name", a.Name, b.Name)
+	}
+}
+
+func TestRunner_Run_dryRun(t *testing.T) {
+	client, srv := setup(t)
+	path := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+	runner := &Runner{Client: client, DryRun: true, Checkpoint: path}
+
+	report, err := runner.Run(context.Background(), UpdateMetadata(map[string]string{"tier": "gold"}), []int{1, 2})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	if !report.DryRun || report.Checked != 2 || report.Succeeded != 0 {
+		t.Errorf("Dry run returned report %+v, want 2 checked", report)
+	}
+	for _, r := range srv.requests {
+		if !strings.HasPrefix(r, "GET ") {
+			t.Errorf("Dry run sent %q, want GET requests only", r)
+		}
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Dry run created a checkpoint, want none")
+	}
+}
+
+func TestRunner_Run_canceled(t *testing.T) {
+	client, _ := setup(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	op := Operation{
+		Name: "cancel after 2",
+		Apply: func(ctx context.Context, client *chargify.Client, id int) error {
+			if id == 2 {
+				cancel()
+			}
+			return nil
+		},
+	}
+	runner := &Runner{Client: client, Workers: 1}
+	report, err := runner.Run(ctx, op, []int{1, 2, 3, 4, 5})
+	if err != context.Canceled {
+		t.Errorf("Run returned error %v, want context.Canceled", err)
+	}
+	if n := len(report.Results); n < 2 || n >= 5 {
+		t.Errorf("Canceled run returned %d results, want the ones done before canceling", n)
+	}
+}
@@ -0,0 +1,88 @@
+package bulk
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+)
+
+// checkpoint is a file recording the result of every subscription done, one
+// JSON object per line. Lines are appended as subscriptions are done, so the
+// file survives the run being killed at any point; a line cut short by a
+// crash is ignored.
+type checkpoint struct {
+	file      *os.File
+	operation string
+	succeeded map[int]bool
+}
+
+type checkpointLine struct {
+	Operation string `json:"operation"`
+	Result
+}
+
+// openCheckpoint opens the checkpoint at path for operation, creating it if
+// it does not exist.
+func openCheckpoint(path, operation string) (*checkpoint, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	cp := &checkpoint{file: f, operation: operation, succeeded: make(map[int]bool)}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var line checkpointLine
+		if json.Unmarshal(scanner.Bytes(), &line) != nil || line.Operation != operation {
+			continue
+		}
+		cp.succeeded[line.SubscriptionId] = line.Status == Succeeded
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := endLine(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return cp, nil
+}
+
+// endLine terminates a line cut short at the end of f, so that lines
+// appended to f start on a line of their own.
+func endLine(f *os.File) error {
+	fi, err := f.Stat()
+	if err != nil || fi.Size() == 0 {
+		return err
+	}
+	last := make([]byte, 1)
+	if _, err := f.ReadAt(last, fi.Size()-1); err != nil {
+		return err
+	}
+	if last[0] != '\n' {
+		_, err = f.Write([]byte{'\n'})
+	}
+	return err
+}
+
+// done reports whether the operation already succeeded for a subscription.
+func (cp *checkpoint) done(id int) bool {
+	return cp.succeeded[id]
+}
+
+// save records a result.
+func (cp *checkpoint) save(res Result) error {
+	b, err := json.Marshal(checkpointLine{Operation: cp.operation, Result: res})
+	if err != nil {
+		return err
+	}
+	if _, err := cp.file.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	return cp.file.Sync()
+}
+
+func (cp *checkpoint) Close() error {
+	return cp.file.Close()
+}
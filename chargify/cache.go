@@ -0,0 +1,258 @@
+package chargify
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultCacheSize is how many responses the cache installed by WithCache
+// holds when none is given.
+const defaultCacheSize = 256
+
+// cacheableResources are the resources whose GET responses are cached. They
+// make up the product catalog, which changes rarely compared to how often it
+// is read.
+var cacheableResources = map[string]bool{
+	"products":         true,
+	"product_families": true,
+	"components":       true,
+}
+
+// A CacheEntry is a response stored in a Cache.
+type CacheEntry struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	// Expires is when the entry must be revalidated with the API.
+	Expires time.Time
+}
+
+// Cache stores API responses by request URL. Implementations must be safe for
+// concurrent use.
+type Cache interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry)
+	Delete(key string)
+
+	// Clear deletes every entry.
+	Clear()
+}
+
+// WithCache makes the client cache the responses of GET requests for
+// products, product families and components in cache, or in an LRUCache of
+// 256 responses if cache is nil. Cached responses are served without calling
+// the API for ttl. Once they expire, they are revalidated with If-None-Match
+// if the API sent an ETag, and fetched again otherwise.
+//
+// Any successful POST, PUT or DELETE request to these resources clears the
+// cache. Changes made outside of the client show up once cached responses
+// expire, or after calling InvalidateCache.
+func WithCache(cache Cache, ttl time.Duration) Option {
+	return func(c *Client) error {
+		if ttl <= 0 {
+			return errors.New("chargify: cache TTL must be positive")
+		}
+		if cache == nil {
+			cache = NewLRUCache(defaultCacheSize)
+		}
+		c.cache = &responseCache{store: cache, ttl: ttl, now: time.Now}
+		return nil
+	}
+}
+
+// InvalidateCache deletes the cached responses of the given URLs, relative to
+// BaseURL as in NewRequest, or all cached responses if no URL is given.
+func (c *Client) InvalidateCache(urls ...string) error {
+	if c.cache == nil {
+		return nil
+	}
+	if len(urls) == 0 {
+		c.cache.store.Clear()
+		return nil
+	}
+	for _, urlStr := range urls {
+		req, err := c.NewRequest("GET", urlStr, nil)
+		if err != nil {
+			return err
+		}
+		c.cache.store.Delete(req.URL.String())
+	}
+	return nil
+}
+
+type responseCache struct {
+	store Cache
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+// cacheable reports whether the responses of requests to path may be cached,
+// i.e. whether it belongs to a catalog resource.
+func (c *Client) cacheable(path string) bool {
+	path = strings.TrimPrefix(path, c.BaseURL.Path)
+	resource := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)[0]
+	return cacheableResources[strings.TrimSuffix(resource, ".json")]
+}
+
+// cacheHandler returns next serving GET requests for catalog resources from
+// the client's cache.
+func (c *Client) cacheHandler(next Handler) Handler {
+	return func(ctx context.Context, req *http.Request) (*http.Response, error) {
+		if !c.cacheable(req.URL.Path) {
+			return next(ctx, req)
+		}
+		if req.Method != "GET" {
+			resp, err := next(ctx, req)
+			if err == nil && resp.StatusCode < 300 {
+				c.cache.store.Clear()
+			}
+			return resp, err
+		}
+
+		key := req.URL.String()
+		entry, ok := c.cache.store.Get(key)
+		if ok && c.cache.now().Before(entry.Expires) {
+			return c.cached(ctx, req, entry), nil
+		}
+		if ok {
+			if etag := entry.Header.Get("ETag"); etag != "" {
+				req = req.Clone(ctx)
+				req.Header.Set("If-None-Match", etag)
+			}
+		}
+
+		resp, err := next(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case resp.StatusCode == http.StatusNotModified && ok:
+			resp.Body.Close()
+			entry = &CacheEntry{
+				StatusCode: entry.StatusCode,
+				Header:     entry.Header,
+				Body:       entry.Body,
+				Expires:    c.cache.now().Add(c.cache.ttl),
+			}
+			c.cache.store.Set(key, entry)
+			return c.cached(ctx, req, entry), nil
+		case resp.StatusCode == http.StatusOK:
+			body, err := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+			c.cache.store.Set(key, &CacheEntry{
+				StatusCode: resp.StatusCode,
+				Header:     resp.Header.Clone(),
+				Body:       body,
+				Expires:    c.cache.now().Add(c.cache.ttl),
+			})
+		}
+		return resp, nil
+	}
+}
+
+// cached returns a response to req made from entry.
+func (c *Client) cached(ctx context.Context, req *http.Request, entry *CacheEntry) *http.Response {
+	if op := OperationFromContext(ctx); op != nil {
+		op.Cached = true
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", entry.StatusCode, http.StatusText(entry.StatusCode)),
+		StatusCode:    entry.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        entry.Header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(entry.Body)),
+		ContentLength: int64(len(entry.Body)),
+		Request:       req,
+	}
+}
+
+// LRUCache is an in-memory Cache holding a fixed number of entries. When it
+// is full, the least recently used entry is evicted.
+type LRUCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List // front is most recently used
+}
+
+type lruItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewLRUCache returns an LRUCache holding up to size entries.
+func NewLRUCache(size int) *LRUCache {
+	if size < 1 {
+		size = 1
+	}
+	return &LRUCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *LRUCache) Get(key string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*lruItem).entry, true
+}
+
+func (c *LRUCache) Set(key string, entry *CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*lruItem).entry = entry
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruItem{key: key, entry: entry})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruItem).key)
+	}
+}
+
+func (c *LRUCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.order.Remove(e)
+		delete(c.entries, key)
+	}
+}
+
+func (c *LRUCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// Len returns the number of entries in the cache.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
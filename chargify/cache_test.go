@@ -0,0 +1,161 @@
+package chargify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// setupCache installs a cache on the test client whose clock the returned
+// function advances.
+func setupCache() (advance func(time.Duration)) {
+	now := time.Unix(0, 0)
+	client.cache = &responseCache{
+		store: NewLRUCache(defaultCacheSize),
+		ttl:   time.Minute,
+		now:   func() time.Time { return now },
+	}
+	return func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestDo_cache(t *testing.T) {
+	setup()
+	defer teardown()
+	advance := setupCache()
+
+	requests := 0
+	mux.HandleFunc("/products", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintf(w, `[{"product": {"id":%d}}]`, requests)
+	})
+
+	ctx := context.Background()
+	for i, wantCached := range []bool{false, true, true} {
+		products, resp, err := client.Products.List(ctx)
+		if err != nil {
+			t.Fatalf("Products.List returned error: %v", err)
+		}
+		if want := []*Product{{Id: 1}}; !reflect.DeepEqual(products, want) {
+			t.Errorf("Products.List call %d returned %+v, want %+v", i, products, want)
+		}
+		if resp.Cached != wantCached {
+			t.Errorf("Products.List call %d Cached is %v, want %v", i, resp.Cached, wantCached)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Server received %d requests, want 1", requests)
+	}
+
+	advance(time.Minute)
+	products, _, _ := client.Products.List(ctx)
+	if want := []*Product{{Id: 2}}; !reflect.DeepEqual(products, want) {
+		t.Errorf("Products.List after expiry returned %+v, want %+v", products, want)
+	}
+}
+
+func TestDo_cacheETag(t *testing.T) {
+	setup()
+	defer teardown()
+	advance := setupCache()
+
+	requests := 0
+	mux.HandleFunc("/products", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `[{"product": {"id":1}}]`)
+	})
+
+	ctx := context.Background()
+	client.Products.List(ctx)
+	advance(time.Minute)
+	products, resp, err := client.Products.List(ctx)
+	if err != nil {
+		t.Fatalf("Products.List returned error: %v", err)
+	}
+	if want := []*Product{{Id: 1}}; !reflect.DeepEqual(products, want) {
+		t.Errorf("Products.List returned %+v, want %+v", products, want)
+	}
+	if requests != 2 || !resp.Cached || resp.StatusCode != http.StatusOK {
+		t.Errorf("Products.List made %d requests and returned status %d cached %v, want a revalidated 200", requests, resp.StatusCode, resp.Cached)
+	}
+
+	client.Products.List(ctx)
+	if requests != 2 {
+		t.Errorf("Server received %d requests, want revalidation to renew the entry", requests)
+	}
+}
+
+func TestDo_cacheInvalidation(t *testing.T) {
+	setup()
+	defer teardown()
+	setupCache()
+
+	requests := map[string]int{}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		requests[r.Method+" "+r.URL.Path]++
+		fmt.Fprint(w, `{}`)
+	})
+
+	ctx := context.Background()
+	get := func(u string) {
+		req, _ := client.NewRequest("GET", u, nil)
+		if _, err := client.Do(ctx, req, nil); err != nil {
+			t.Fatalf("Do returned error: %v", err)
+		}
+	}
+
+	get("products")
+	get("product_families/1/components")
+	get("subscriptions/1")
+	get("subscriptions/1")
+	get("products")
+	if requests["GET /products"] != 1 || requests["GET /subscriptions/1"] != 2 {
+		t.Errorf("Server received %v, want catalog requests cached only", requests)
+	}
+
+	client.InvalidateCache("products")
+	get("products")
+	get("product_families/1/components")
+	if requests["GET /products"] != 2 || requests["GET /product_families/1/components"] != 1 {
+		t.Errorf("Server received %v, want only products invalidated", requests)
+	}
+
+	req, _ := client.NewRequest("PUT", "products/1", &Product{Name: "Gold"})
+	client.Do(ctx, req, nil)
+	get("products")
+	get("product_families/1/components")
+	if requests["GET /products"] != 3 || requests["GET /product_families/1/components"] != 2 {
+		t.Errorf("Server received %v, want the catalog cache cleared by an update", requests)
+	}
+}
+
+func TestLRUCache(t *testing.T) {
+	c := NewLRUCache(2)
+	a, b, d := &CacheEntry{Body: []byte("a")}, &CacheEntry{Body: []byte("b")}, &CacheEntry{Body: []byte("d")}
+	c.Set("a", a)
+	c.Set("b", b)
+	c.Get("a")
+	c.Set("d", d)
+
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("LRUCache kept b, want the least recently used entry evicted")
+	}
+	if got, ok := c.Get("a"); !ok || got != a {
+		t.Errorf("LRUCache.Get(a) returned %v, %v, want a", got, ok)
+	}
+	c.Delete("a")
+	if c.Len() != 1 {
+		t.Errorf("LRUCache has %d entries after a delete, want 1", c.Len())
+	}
+	c.Clear()
+	if c.Len() != 0 {
+		t.Errorf("LRUCache has %d entries after Clear, want 0", c.Len())
+	}
+}
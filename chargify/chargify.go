@@ -4,40 +4,64 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"log/slog"
 	"net/http"
 	"net/url"
-	"text/template"
+	"reflect"
 	"time"
+
+	"github.com/google/go-querystring/query"
 )
 
 const (
 	libraryVersion = "0"
 	userAgent      = "go-chargify/" + libraryVersion
-)
 
-var (
-	baseURLTemplate *template.Template
-)
+	// defaultPerPage is the page size Chargify uses when per_page is not
+	// specified on a paginated request.
+	defaultPerPage = 20
 
-func init() {
-	var err error
-	baseURLTemplate, err = template.New("baseUrl").Parse("https://{{.Subdomain}}.chargify.com/")
-	if err != nil {
-		panic(err)
-	}
-}
+	// maxPerPage is the largest page size Chargify accepts.
+	maxPerPage = 200
+)
 
 type Client struct {
-	client        *http.Client
-	BaseURL       *url.URL
-	ApiKey        string
-	UserAgent     string
-	common        service
-	Subscriptions *SubscriptionsService
-	Products      *ProductsService
+	client             *http.Client
+	BaseURL            *url.URL
+	ApiKey             string
+	UserAgent          string
+	timeout            time.Duration
+	retryPolicy        RetryPolicy
+	logger             *slog.Logger
+	dumpBodies         bool
+	limiter            *limiter
+	inFlight           chan struct{}
+	cache              *responseCache
+	breaker            *CircuitBreaker
+	states             *stateTracker
+	schemaCheck        func(*http.Request, []SchemaMismatch)
+	tokenizedOnly      bool
+	portalLinks        *portalLinks
+	hooks              Hooks
+	middleware         []Middleware
+	common             service
+	Subscriptions      *SubscriptionsService
+	Products           *ProductsService
+	Events             *EventsService
+	Transactions       *TransactionsService
+	SubscriptionNotes  *SubscriptionNotesService
+	CustomFields       *CustomFieldsService
+	SubscriptionGroups *SubscriptionGroupsService
+	ProformaInvoices   *ProformaInvoicesService
+	Customers          *CustomersService
+	PaymentProfiles    *PaymentProfilesService
+	BillingPortal      *BillingPortalService
+	ReferralCodes      *ReferralCodesService
+	ReasonCodes        *ReasonCodesService
 }
 
 type service struct {
@@ -48,24 +72,104 @@ type service struct {
 // provided, http.DefaultClient will be used. To use API methods which require
 // authentication, provide an http.Client that will perform the authentication
 // for you (such as that provided by the golang.org/x/oauth2 library).
+//
+// NewClient does not check subdomain; New does, and takes options for the
+// other settings of the client.
 func NewClient(subdomain, api_key string, httpClient *http.Client) *Client {
-	if httpClient == nil {
-		httpClient = http.DefaultClient
+	c := newClient(api_key)
+	if httpClient != nil {
+		c.client = httpClient
 	}
-	var buf bytes.Buffer
-	err := baseURLTemplate.Execute(&buf, struct{ Subdomain string }{subdomain})
-	if err != nil {
-		panic(err)
+	c.BaseURL, _ = url.Parse("https://" + subdomain + ".chargify.com/")
+	return c
+}
+
+// New returns a new Chargify API client for the site at subdomain,
+// authenticating with apiKey and configured by opts. It returns an error if
+// an option is invalid or no base URL can be derived from subdomain.
+func New(subdomain, apiKey string, opts ...Option) (*Client, error) {
+	c := newClient(apiKey)
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
 	}
-	baseURL, _ := url.Parse(buf.String())
 
-	c := &Client{client: httpClient, BaseURL: baseURL, UserAgent: userAgent, ApiKey: api_key}
+	if c.BaseURL == nil {
+		if subdomain == "" {
+			return nil, errors.New("chargify: a subdomain or base URL is required")
+		}
+		baseURL, err := url.Parse("https://" + subdomain + ".chargify.com/")
+		if err != nil {
+			return nil, fmt.Errorf("chargify: invalid subdomain %q: %v", subdomain, err)
+		}
+		c.BaseURL = baseURL
+	}
+	if c.timeout > 0 {
+		httpClient := *c.client
+		httpClient.Timeout = c.timeout
+		c.client = &httpClient
+	}
+	return c, nil
+}
+
+// newClient returns a client with default settings and no base URL.
+func newClient(apiKey string) *Client {
+	c := &Client{
+		client:      http.DefaultClient,
+		UserAgent:   userAgent,
+		ApiKey:      apiKey,
+		portalLinks: newPortalLinks(),
+	}
 	c.common.client = c
 	c.Subscriptions = (*SubscriptionsService)(&c.common)
 	c.Products = (*ProductsService)(&c.common)
+	c.Events = (*EventsService)(&c.common)
+	c.Transactions = (*TransactionsService)(&c.common)
+	c.SubscriptionNotes = (*SubscriptionNotesService)(&c.common)
+	c.CustomFields = (*CustomFieldsService)(&c.common)
+	c.SubscriptionGroups = (*SubscriptionGroupsService)(&c.common)
+	c.ProformaInvoices = (*ProformaInvoicesService)(&c.common)
+	c.Customers = (*CustomersService)(&c.common)
+	c.PaymentProfiles = (*PaymentProfilesService)(&c.common)
+	c.BillingPortal = (*BillingPortalService)(&c.common)
+	c.ReferralCodes = (*ReferralCodesService)(&c.common)
+	c.ReasonCodes = (*ReasonCodesService)(&c.common)
 	return c
 }
 
+// ListOptions specifies the optional parameters to various List methods that
+// support pagination.
+type ListOptions struct {
+	// For paginated result sets, page of results to retrieve.
+	Page int `url:"page,omitempty"`
+
+	// For paginated result sets, the number of results to include per page.
+	PerPage int `url:"per_page,omitempty"`
+}
+
+// addOptions adds the parameters in opt as URL query parameters to s. opt
+// must be a struct whose fields may contain "url" tags.
+func addOptions(s string, opt interface{}) (string, error) {
+	v := reflect.ValueOf(opt)
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return s, nil
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return s, err
+	}
+
+	qs, err := query.Values(opt)
+	if err != nil {
+		return s, err
+	}
+
+	u.RawQuery = qs.Encode()
+	return u.String(), nil
+}
+
 // NewRequest creates an API request. A relative URL can be provided in urlStr,
 // in which case it is resolved relative to the BaseURL of the Client.
 // Relative URLs should always be specified without a preceding slash. If
@@ -81,11 +185,17 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 
 	var buf io.ReadWriter
 	if body != nil {
-		buf = new(bytes.Buffer)
-		err := json.NewEncoder(buf).Encode(body)
+		b := new(bytes.Buffer)
+		err := json.NewEncoder(b).Encode(body)
 		if err != nil {
 			return nil, err
 		}
+		if c.tokenizedOnly {
+			if err := checkTokenized(b.Bytes()); err != nil {
+				return nil, err
+			}
+		}
+		buf = b
 	}
 
 	req, err := http.NewRequest(method, u.String(), buf)
@@ -120,6 +230,10 @@ type Response struct {
 	PrevPage  int
 	FirstPage int
 	LastPage  int
+
+	// Cached reports whether the response was served from the client's
+	// cache (see WithCache) rather than by the API.
+	Cached bool
 }
 
 // newResponse creates a new Response for the provided http.Response.
@@ -129,6 +243,31 @@ func newResponse(r *http.Response) *Response {
 	return response
 }
 
+// setPageValues fills in the page values of r for a list request made with
+// opt that returned n results. Chargify does not send pagination links, so a
+// full page is the only hint that another page may follow.
+func (r *Response) setPageValues(opt *ListOptions, n int) {
+	if r == nil {
+		return
+	}
+	page, perPage := 1, defaultPerPage
+	if opt != nil {
+		if opt.Page > 0 {
+			page = opt.Page
+		}
+		if opt.PerPage > 0 {
+			perPage = opt.PerPage
+		}
+	}
+	r.FirstPage = 1
+	if page > 1 {
+		r.PrevPage = page - 1
+	}
+	if n >= perPage {
+		r.NextPage = page + 1
+	}
+}
+
 type FormattedTime struct {
 	*time.Time
 }
@@ -154,15 +293,33 @@ func NewFormattedTime(input string) *FormattedTime {
 // JSON decoded and stored in the value pointed to by v, or returned as an
 // error if an API error has occurred. If v implements the io.Writer
 // interface, the raw response body will be written to v, without attempting to
-// first decode it. If rate limit is exceeded and reset time is in the future,
-// Do returns *RateLimitError immediately without making a network API call.
+// first decode it. A 429 Too Many Requests response is returned as a
+// *RateLimitError.
+//
+// If the client has a rate limit or a cap on requests in flight, Do waits
+// until they allow the request to be sent.
+//
+// The request is passed through the client's middleware, with an Operation
+// naming the calling service method in its context.
+//
+// Mutating requests are sent with an Idempotency-Key header. The key is taken
+// from ctx (see WithIdempotencyKey) or generated, unless req already has one.
 //
 // The provided ctx must be non-nil. If it is canceled or times out,
 // ctx.Err() will be returned.
-func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Response, error) {
+func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (response *Response, err error) {
+	ctx = context.WithValue(ctx, operationContextKey{}, newOperation(ctx, req))
 	ctx, req = withContext(ctx, req)
+	setIdempotencyKey(ctx, req)
+	if c.hooks.OnError != nil {
+		defer func() {
+			if err != nil {
+				c.hooks.OnError(ctx, req, err)
+			}
+		}()
+	}
 
-	resp, err := c.client.Do(req)
+	resp, err := c.handler()(ctx, req)
 	if err != nil {
 		// If we got an error, and the context has been canceled,
 		// the context's error is probably more useful.
@@ -181,7 +338,8 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Res
 		resp.Body.Close()
 	}()
 
-	response := newResponse(resp)
+	response = newResponse(resp)
+	response.Cached = OperationFromContext(ctx).Cached
 
 	err = CheckResponse(resp)
 	if err != nil {
@@ -193,6 +351,8 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Res
 	if v != nil {
 		if w, ok := v.(io.Writer); ok {
 			io.Copy(w, resp.Body)
+		} else if c.schemaCheck != nil {
+			err = c.decodeChecked(req, resp.Body, v)
 		} else {
 			err = json.NewDecoder(resp.Body).Decode(v)
 			if err == io.EOF {
@@ -208,6 +368,95 @@ func withContext(ctx context.Context, req *http.Request) (context.Context, *http
 	return ctx, req.WithContext(ctx)
 }
 
+// send sends req, retrying it as allowed by the client's retry policy. The
+// returned response is the one of the last attempt.
+func (c *Client) send(ctx context.Context, req *http.Request) (*http.Response, error) {
+	op := OperationFromContext(ctx)
+	for attempt := 0; ; attempt++ {
+		if op != nil {
+			op.Retries = attempt
+		}
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		var probe bool
+		if c.breaker != nil {
+			var err error
+			if probe, err = c.breaker.allow(); err != nil {
+				return nil, err
+			}
+		}
+		release, err := c.limit(ctx)
+		if err != nil {
+			if c.breaker != nil {
+				c.breaker.done(ctx, probe, nil, err)
+			}
+			return nil, err
+		}
+		resp, err := c.roundTrip(ctx, req)
+		if c.breaker != nil {
+			c.breaker.done(ctx, probe, resp, err)
+		}
+		if resp != nil && c.limiter != nil {
+			c.limiter.observe(resp)
+		}
+		if resp != nil && err == nil {
+			resp.Body = &releaseBody{ReadCloser: resp.Body, release: release}
+		} else {
+			// Responses returned with an error, e.g. after too many
+			// redirects, have their body closed by net/http already.
+			release()
+		}
+		if !c.retryPolicy.shouldRetry(attempt, req, resp, err) {
+			return resp, err
+		}
+
+		wait := c.retryPolicy.backoff(attempt, resp)
+		if resp != nil {
+			io.CopyN(ioutil.Discard, resp.Body, 512)
+			resp.Body.Close()
+		}
+		if c.logger != nil {
+			c.logger.LogAttrs(ctx, slog.LevelDebug, "chargify: retrying request",
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+				slog.Int("attempt", attempt+1),
+				slog.Duration("wait", wait))
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// roundTrip sends req once, logging it and calling the client's hooks around
+// it.
+func (c *Client) roundTrip(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if c.hooks.BeforeRequest != nil {
+		c.hooks.BeforeRequest(ctx, req)
+	}
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	elapsed := time.Since(start)
+	if c.logEnabled(ctx) {
+		c.logRoundTrip(ctx, req, resp, err, elapsed)
+	}
+	if c.hooks.AfterResponse != nil {
+		c.hooks.AfterResponse(ctx, req, resp, err, elapsed)
+	}
+	return resp, err
+}
+
 // CheckResponse checks the API response for errors, and returns them if
 // present. A response is considered an error if it has a status code outside
 // the 200 range.
@@ -224,6 +473,14 @@ func CheckResponse(r *http.Response) error {
 	if err == nil && data != nil {
 		json.Unmarshal(data, errorResponse)
 	}
+	if r.StatusCode == http.StatusTooManyRequests {
+		retryAfter, _ := retryAfter(r)
+		return &RateLimitError{
+			Response:   r,
+			RetryAfter: retryAfter,
+			Errors:     errorResponse.Errors,
+		}
+	}
 	return errorResponse
 }
 
@@ -241,3 +498,17 @@ func (r *ErrorResponse) Error() string {
 		r.Response.Request.Method, r.Response.Request.URL,
 		r.Response.StatusCode, r.Errors)
 }
+
+// RateLimitError occurs when Chargify rejects a request for exceeding its
+// rate limit with 429 Too Many Requests.
+type RateLimitError struct {
+	Response   *http.Response // HTTP response that caused this error
+	RetryAfter time.Duration  // wait requested by Chargify, if any
+	Errors     []string       `json:"errors"` // error messages
+}
+
+func (r *RateLimitError) Error() string {
+	return fmt.Sprintf("%v %v: %d rate limit exceeded, retry after %v %+v",
+		r.Response.Request.Method, r.Response.Request.URL,
+		r.Response.StatusCode, r.RetryAfter, r.Errors)
+}
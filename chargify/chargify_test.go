@@ -11,6 +11,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 const (
@@ -218,6 +219,26 @@ func TestDo_redirectLoop(t *testing.T) {
 	}
 }
 
+func TestDo_redirectLoopReleasesInFlight(t *testing.T) {
+	setup()
+	defer teardown()
+	WithMaxInFlight(1)(client)
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/", http.StatusFound)
+	})
+
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		req, _ := client.NewRequest("GET", "/", nil)
+		_, err := client.Do(ctx, req, nil)
+		cancel()
+		if _, ok := err.(*url.Error); !ok {
+			t.Fatalf("Do call %d returned %v, want a redirect error", i+1, err)
+		}
+	}
+}
+
 func TestDo_noContent(t *testing.T) {
 	setup()
 	defer teardown()
@@ -0,0 +1,119 @@
+package chargify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ChargifyJSKeys is the key pair of a site for Chargify.js, found in its
+// integration settings. The public key is sent to browsers, the private key
+// must stay on the server.
+type ChargifyJSKeys struct {
+	PublicKey  string
+	PrivateKey string
+}
+
+// ChargifyJSConfig holds the options the Chargify.js form is loaded with. It
+// encodes to the JSON names Chargify.js expects, so it can be passed to the
+// page as is.
+type ChargifyJSConfig struct {
+	PublicKey     string `json:"publicKey"`
+	SecurityToken string `json:"securityToken,omitempty"`
+	ServerHost    string `json:"serverHost"`
+}
+
+// SecurityToken returns a new security token, a JWT signed with the private
+// key that lets Chargify.js tokenize a card once. Sites requiring security
+// tokens reject forms loaded without one.
+func (k *ChargifyJSKeys) SecurityToken() (string, error) {
+	if k.PublicKey == "" || k.PrivateKey == "" {
+		return "", errors.New("chargify: Chargify.js keys are incomplete")
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss": k.PublicKey,
+		"jti": NewIdempotencyKey(),
+		"iat": time.Now().Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	token := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	mac := hmac.New(sha256.New, []byte(k.PrivateKey))
+	mac.Write([]byte(token))
+	return token + "." + enc.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Config returns the Chargify.js options for a form posting to serverHost,
+// the URL of the site, such as "https://acme.chargify.com", with a new
+// security token.
+func (k *ChargifyJSKeys) Config(serverHost string) (*ChargifyJSConfig, error) {
+	token, err := k.SecurityToken()
+	if err != nil {
+		return nil, err
+	}
+	return &ChargifyJSConfig{PublicKey: k.PublicKey, SecurityToken: token, ServerHost: serverHost}, nil
+}
+
+// ErrRawCardData is returned by Client.NewRequest, and so by every method
+// sending a request, for request bodies with a full card number or CVV when
+// the client only allows tokenized cards.
+var ErrRawCardData = errors.New("chargify: raw card data is not allowed, send a Chargify.js token instead")
+
+// WithTokenizedCardsOnly makes the client refuse to send full card numbers
+// and CVVs, failing such requests with ErrRawCardData before they leave the
+// process. Cards then have to be sent as Chargify.js tokens, keeping card
+// data, and the compliance scope that comes with it, off your servers.
+func WithTokenizedCardsOnly() Option {
+	return func(c *Client) error {
+		c.tokenizedOnly = true
+		return nil
+	}
+}
+
+// rawCardFields are the JSON fields of CreditCard holding raw card data.
+var rawCardFields = map[string]bool{"full_number": true, "cvv": true}
+
+// checkTokenized returns ErrRawCardData if the JSON document data sets a raw
+// card field anywhere.
+func checkTokenized(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if hasRawCardData(v) {
+		return ErrRawCardData
+	}
+	return nil
+}
+
+func hasRawCardData(v interface{}) bool {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for name, value := range v {
+			if rawCardFields[name] && value != nil && value != "" {
+				return true
+			}
+			if hasRawCardData(value) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, value := range v {
+			if hasRawCardData(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
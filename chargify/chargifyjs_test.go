@@ -0,0 +1,100 @@
+package chargify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChargifyJSKeys_SecurityToken(t *testing.T) {
+	keys := &ChargifyJSKeys{PublicKey: "chjs_public", PrivateKey: "private"}
+	token, err := keys.SecurityToken()
+	if err != nil {
+		t.Fatalf("SecurityToken returned error: %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("SecurityToken returned %q, want a JWT", token)
+	}
+	mac := hmac.New(sha256.New, []byte("private"))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if sig, _ := base64.RawURLEncoding.DecodeString(parts[2]); !hmac.Equal(sig, mac.Sum(nil)) {
+		t.Errorf("SecurityToken signature does not match the private key")
+	}
+
+	var claims struct {
+		Iss string `json:"iss"`
+		Jti string `json:"jti"`
+		Iat int64  `json:"iat"`
+	}
+	b, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	if err := json.Unmarshal(b, &claims); err != nil {
+		t.Fatalf("SecurityToken claims %s are invalid: %v", b, err)
+	}
+	if claims.Iss != "chjs_public" || claims.Jti == "" || time.Since(time.Unix(claims.Iat, 0)) > time.Minute {
+		t.Errorf("SecurityToken claims are %+v, want the public key, an id and the current time", claims)
+	}
+
+	if other, _ := keys.SecurityToken(); other == token {
+		t.Errorf("SecurityToken returned the same token twice")
+	}
+	if _, err := (&ChargifyJSKeys{PublicKey: "chjs_public"}).SecurityToken(); err == nil {
+		t.Errorf("SecurityToken without a private key returned no error")
+	}
+}
+
+func TestChargifyJSKeys_Config(t *testing.T) {
+	keys := &ChargifyJSKeys{PublicKey: "chjs_public", PrivateKey: "private"}
+	config, err := keys.Config("https://acme.chargify.com")
+	if err != nil {
+		t.Fatalf("Config returned error: %v", err)
+	}
+
+	b, _ := json.Marshal(config)
+	var got map[string]string
+	json.Unmarshal(b, &got)
+	if got["publicKey"] != "chjs_public" || got["serverHost"] != "https://acme.chargify.com" || got["securityToken"] == "" {
+		t.Errorf("Config encodes to %s, want the Chargify.js option names", b)
+	}
+}
+
+func TestWithTokenizedCardsOnly(t *testing.T) {
+	setup()
+	defer teardown()
+	WithTokenizedCardsOnly()(client)
+
+	mux.HandleFunc("/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"subscription": {"id": 1}}`))
+	})
+
+	_, _, err := client.Subscriptions.Create(context.Background(), &Subscription{
+		ProductHandle:        "basic",
+		CreditCardAttributes: &CreditCard{FullNumber: "4111111111111111", CVV: "123"},
+	})
+	if err != ErrRawCardData {
+		t.Errorf("Subscriptions.Create with a card number returned error %v, want ErrRawCardData", err)
+	}
+
+	_, _, err = client.SubscriptionGroups.Signup(context.Background(), &SubscriptionGroupSignup{
+		Subscriptions:        []*Subscription{{ProductHandle: "basic"}},
+		CreditCardAttributes: &CreditCard{CVV: "123"},
+	})
+	if err != ErrRawCardData {
+		t.Errorf("SubscriptionGroups.Signup with a CVV returned error %v, want ErrRawCardData", err)
+	}
+
+	_, _, err = client.Subscriptions.Create(context.Background(), &Subscription{
+		ProductHandle:        "basic",
+		CreditCardAttributes: &CreditCard{ChargifyToken: "tok_9g6hw85pnpt6knmskpwp4ttt"},
+	})
+	if err != nil {
+		t.Errorf("Subscriptions.Create with a token returned error: %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+// Package chargifytest helps test code against the Chargify API schema this
+// module knows about.
+//
+// Validate recorded API responses against the types they decode into:
+//
+//	func TestFixtures(t *testing.T) {
+//		chargifytest.CheckFixture(t, "testdata/subscription.json", new(chargify.SubscriptionWrapper))
+//		chargifytest.CheckFixture(t, "testdata/events.json", new([]chargify.EventWrapper))
+//	}
+//
+// or fail tests talking to the API, such as integration tests run in CI,
+// when its responses drift from the types:
+//
+//	client, err := chargify.New(subdomain, apiKey, chargifytest.SchemaCheck(t))
+package chargifytest
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/m0dd3r/go-chargify/chargify"
+)
+
+// CheckFixture reports an error to t for every mismatch between the JSON
+// document in the file at path and the type of v, as found by
+// chargify.CheckSchema.
+func CheckFixture(t testing.TB, path string, v interface{}) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("chargifytest: %v", err)
+		return
+	}
+	for _, m := range chargify.CheckSchema(data, v) {
+		t.Errorf("%s: %v (value %s)", path, &m, m.Value)
+	}
+}
+
+// SchemaCheck returns an option making the client report an error to t for
+// every mismatch between an API response and the type it is decoded into.
+func SchemaCheck(t testing.TB) chargify.Option {
+	return chargify.WithSchemaCheck(func(req *http.Request, mismatches []chargify.SchemaMismatch) {
+		t.Helper()
+		for _, m := range mismatches {
+			t.Errorf("%s %s: %v (value %s)", req.Method, req.URL.Path, &m, m.Value)
+		}
+	})
+}
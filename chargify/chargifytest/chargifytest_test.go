@@ -0,0 +1,59 @@
+package chargifytest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/m0dd3r/go-chargify/chargify"
+)
+
+// recorder records the errors reported to it instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestCheckFixture(t *testing.T) {
+	r := &recorder{TB: t}
+	CheckFixture(r, "testdata/subscription.json", new(chargify.SubscriptionWrapper))
+
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "unknown field subscription.tier") {
+		t.Errorf("CheckFixture reported %q, want the unknown tier field", r.errors)
+	}
+
+	r.errors = nil
+	CheckFixture(r, "testdata/missing.json", new(chargify.SubscriptionWrapper))
+	if len(r.errors) != 1 {
+		t.Errorf("CheckFixture of a missing file reported %q, want an error", r.errors)
+	}
+}
+
+func TestSchemaCheck(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"customer": {"id": 1, "locale": "en"}}`)
+	}))
+	defer ts.Close()
+
+	r := &recorder{TB: t}
+	client, err := chargify.New("", "", chargify.WithBaseURL(ts.URL), SchemaCheck(r))
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	if _, _, err := client.Customers.Get(context.Background(), 1); err != nil {
+		t.Fatalf("Customers.Get returned error: %v", err)
+	}
+
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "GET /customers/1") {
+		t.Errorf("SchemaCheck reported %q, want the unknown locale field", r.errors)
+	}
+}
@@ -0,0 +1,226 @@
+package chargify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultFailureThreshold = 5
+	defaultSuccessThreshold = 1
+	defaultCooldown         = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned by Do for requests rejected without being sent
+// because the client's circuit breaker is open.
+var ErrCircuitOpen = errors.New("chargify: circuit breaker is open")
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through.
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen rejects every request with ErrCircuitOpen.
+	CircuitOpen
+
+	// CircuitHalfOpen lets one trial request through at a time to find out
+	// whether the API has recovered.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// A Clock tells the time. It lets tests control the time seen by a
+// CircuitBreaker.
+type Clock interface {
+	Now() time.Time
+}
+
+// CircuitBreaker stops a client from sending requests while Chargify appears
+// to be down, so that callers fail fast instead of waiting on timeouts.
+//
+// The breaker opens after FailureThreshold consecutive requests fail without
+// a response or with a 5xx status. While it is open, requests fail with
+// ErrCircuitOpen. After Cooldown it becomes half-open and lets one trial
+// request through at a time: SuccessThreshold successful trials close it
+// again, and a failed one reopens it. Other responses, including 4xx and
+// 429, count as successes.
+//
+// The zero value is a breaker with default settings. A CircuitBreaker may be
+// shared by several clients, and must not be copied after first use.
+type CircuitBreaker struct {
+	// FailureThreshold is how many consecutive failures open the circuit.
+	// Default: 5.
+	FailureThreshold int
+
+	// SuccessThreshold is how many successful trial requests close the
+	// circuit. Default: 1.
+	SuccessThreshold int
+
+	// Cooldown is how long the circuit stays open before it becomes
+	// half-open. Default: 30s.
+	Cooldown time.Duration
+
+	// Clock is the time source of the breaker. Default: the system clock.
+	Clock Clock
+
+	// OnStateChange, if set, is called whenever the state of the breaker
+	// changes. It must not call back into the breaker.
+	OnStateChange func(from, to CircuitState)
+
+	mu        sync.Mutex
+	state     CircuitState
+	failures  int
+	successes int
+	openedAt  time.Time
+	probing   bool // a trial request is in flight
+}
+
+// WithCircuitBreaker makes the client send requests through b. Every attempt
+// of a request counts, including retries, and rejected requests are not
+// retried. If b is nil, a breaker with default settings is used.
+func WithCircuitBreaker(b *CircuitBreaker) Option {
+	return func(c *Client) error {
+		if b == nil {
+			b = new(CircuitBreaker)
+		}
+		if b.FailureThreshold < 0 || b.SuccessThreshold < 0 || b.Cooldown < 0 {
+			return errors.New("chargify: circuit breaker settings must not be negative")
+		}
+		c.breaker = b
+		return nil
+	}
+}
+
+// State returns the current state of the breaker, e.g. for health checks.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && b.cooledDown() {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// allow reports whether a request may be sent, and whether it is the trial
+// request of a half-open breaker. Every allowed request must be followed by a
+// call to done with the same probe value.
+func (b *CircuitBreaker) allow() (probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && b.cooledDown() {
+		b.setState(CircuitHalfOpen)
+	}
+	switch b.state {
+	case CircuitOpen:
+		return false, ErrCircuitOpen
+	case CircuitHalfOpen:
+		if b.probing {
+			return false, ErrCircuitOpen
+		}
+		b.probing = true
+		return true, nil
+	}
+	return false, nil
+}
+
+// done records the outcome of a request let through by allow. While the
+// breaker is half-open, only the trial request counts; requests sent before
+// the breaker opened do not. Requests abandoned by their caller do not count
+// either.
+func (b *CircuitBreaker) done(ctx context.Context, probe bool, resp *http.Response, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe {
+		b.probing = false
+	}
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+	failed := err != nil || resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented
+
+	switch b.state {
+	case CircuitClosed:
+		if !failed {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.failureThreshold() {
+			b.open()
+		}
+	case CircuitHalfOpen:
+		if !probe {
+			return
+		}
+		if failed {
+			b.open()
+			return
+		}
+		b.successes++
+		if b.successes >= b.successThreshold() {
+			b.failures, b.successes = 0, 0
+			b.setState(CircuitClosed)
+		}
+	}
+}
+
+func (b *CircuitBreaker) open() {
+	b.openedAt = b.now()
+	b.successes = 0
+	b.setState(CircuitOpen)
+}
+
+func (b *CircuitBreaker) setState(state CircuitState) {
+	if from := b.state; from != state {
+		b.state = state
+		if b.OnStateChange != nil {
+			b.OnStateChange(from, state)
+		}
+	}
+}
+
+func (b *CircuitBreaker) cooledDown() bool {
+	cooldown := b.Cooldown
+	if cooldown == 0 {
+		cooldown = defaultCooldown
+	}
+	return !b.now().Before(b.openedAt.Add(cooldown))
+}
+
+func (b *CircuitBreaker) now() time.Time {
+	if b.Clock == nil {
+		return time.Now()
+	}
+	return b.Clock.Now()
+}
+
+func (b *CircuitBreaker) failureThreshold() int {
+	if b.FailureThreshold == 0 {
+		return defaultFailureThreshold
+	}
+	return b.FailureThreshold
+}
+
+func (b *CircuitBreaker) successThreshold() int {
+	if b.SuccessThreshold == 0 {
+		return defaultSuccessThreshold
+	}
+	return b.SuccessThreshold
+}
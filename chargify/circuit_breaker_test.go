@@ -0,0 +1,143 @@
+package chargify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func TestCircuitBreaker(t *testing.T) {
+	setup()
+	defer teardown()
+
+	status := http.StatusServiceUnavailable
+	requests := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(status)
+	})
+
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	var changes []string
+	breaker := &CircuitBreaker{
+		FailureThreshold: 2,
+		Cooldown:         time.Minute,
+		Clock:            clock,
+		OnStateChange: func(from, to CircuitState) {
+			changes = append(changes, from.String()+" -> "+to.String())
+		},
+	}
+	client.breaker = breaker
+
+	do := func() error {
+		req, _ := client.NewRequest("GET", "/", nil)
+		_, err := client.Do(context.Background(), req, nil)
+		return err
+	}
+
+	do()
+	do()
+	if got := breaker.State(); got != CircuitOpen {
+		t.Fatalf("State after 2 failures is %v, want open", got)
+	}
+	if err := do(); !errors.Is(err, ErrCircuitOpen) || requests != 2 {
+		t.Errorf("Do returned %v after %d requests, want ErrCircuitOpen without a request", err, requests)
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+	if got := breaker.State(); got != CircuitHalfOpen {
+		t.Errorf("State after cooldown is %v, want half-open", got)
+	}
+	do()
+	if got := breaker.State(); got != CircuitOpen || requests != 3 {
+		t.Errorf("State after a failed trial is %v after %d requests, want open after 3", got, requests)
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+	status = http.StatusNotFound
+	if err := do(); errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Do returned %v for a trial request, want it sent", err)
+	}
+	if got := breaker.State(); got != CircuitClosed {
+		t.Errorf("State after a successful trial is %v, want closed", got)
+	}
+
+	want := []string{"closed -> open", "open -> half-open", "half-open -> open", "open -> half-open", "half-open -> closed"}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("OnStateChange saw %q, want %q", changes, want)
+	}
+}
+
+func TestCircuitBreaker_halfOpenSingleTrial(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	b := &CircuitBreaker{FailureThreshold: 1, SuccessThreshold: 2, Clock: clock}
+	ctx := context.Background()
+
+	b.allow()
+	b.done(ctx, false, nil, errors.New("connection refused"))
+	clock.now = clock.now.Add(defaultCooldown)
+
+	probe, err := b.allow()
+	if err != nil || !probe {
+		t.Fatalf("allow returned %v, %v for the first trial, want a probe", probe, err)
+	}
+	if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("allow returned %v during a trial, want ErrCircuitOpen", err)
+	}
+	b.done(ctx, true, &http.Response{StatusCode: http.StatusOK}, nil)
+	if got := b.State(); got != CircuitHalfOpen {
+		t.Errorf("State after 1 of 2 successful trials is %v, want half-open", got)
+	}
+	b.allow()
+	b.done(ctx, true, &http.Response{StatusCode: http.StatusOK}, nil)
+	if got := b.State(); got != CircuitClosed {
+		t.Errorf("State after 2 successful trials is %v, want closed", got)
+	}
+}
+
+func TestCircuitBreaker_canceledRequests(t *testing.T) {
+	b := &CircuitBreaker{FailureThreshold: 1}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	b.allow()
+	b.done(ctx, false, nil, ctx.Err())
+	if got := b.State(); got != CircuitClosed {
+		t.Errorf("State after a canceled request is %v, want closed", got)
+	}
+}
+
+func TestCircuitBreaker_requestInFlightWhenHalfOpen(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	b := &CircuitBreaker{FailureThreshold: 1, Clock: clock}
+	ctx := context.Background()
+	ok := &http.Response{StatusCode: http.StatusOK}
+
+	// A slow request is sent while the breaker is closed, and another one
+	// opens it.
+	slow, _ := b.allow()
+	b.allow()
+	b.done(ctx, false, nil, errors.New("connection refused"))
+	clock.now = clock.now.Add(defaultCooldown)
+
+	probe, _ := b.allow()
+	b.done(ctx, slow, ok, nil)
+	if got := b.State(); got != CircuitHalfOpen {
+		t.Errorf("State after an earlier request succeeded is %v, want half-open", got)
+	}
+	if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("allow returned %v while the trial is in flight, want ErrCircuitOpen", err)
+	}
+
+	b.done(ctx, probe, ok, nil)
+	if got := b.State(); got != CircuitClosed {
+		t.Errorf("State after the trial succeeded is %v, want closed", got)
+	}
+}
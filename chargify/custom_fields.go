@@ -0,0 +1,270 @@
+package chargify
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// ResourceType is the kind of resource custom fields are attached to.
+type ResourceType string
+
+const (
+	ResourceCustomers     ResourceType = "customers"
+	ResourceSubscriptions ResourceType = "subscriptions"
+)
+
+// MetafieldInputType controls how a custom field is edited in the Chargify
+// admin and portal.
+type MetafieldInputType string
+
+const (
+	MetafieldText     MetafieldInputType = "text"
+	MetafieldDropdown MetafieldInputType = "dropdown"
+	MetafieldRadio    MetafieldInputType = "radio"
+)
+
+// Metafield is the definition of a custom field for a resource type. Enum
+// lists the allowed values of dropdown and radio fields.
+type Metafield struct {
+	Id          int                `json:"id,omitempty"`
+	Name        string             `json:"name,omitempty"`
+	CurrentName string             `json:"current_name,omitempty"`
+	InputType   MetafieldInputType `json:"input_type,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	DataCount   int                `json:"data_count,omitempty"`
+}
+
+// Metadata is the value of a custom field on a single resource.
+type Metadata struct {
+	Id         int    `json:"id,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Value      string `json:"value"`
+	ResourceId int    `json:"resource_id,omitempty"`
+}
+
+// MetadataFilter matches resources by the values of their custom fields,
+// keyed by field name.
+type MetadataFilter map[string]string
+
+// EncodeValues implements query.Encoder, encoding f as key[name]=value pairs.
+func (f MetadataFilter) EncodeValues(key string, v *url.Values) error {
+	for name, value := range f {
+		v.Add(fmt.Sprintf("%s[%s]", key, name), value)
+	}
+	return nil
+}
+
+type metafieldsWrapper struct {
+	Metafields []*Metafield `json:"metafields"`
+}
+
+type metadataWrapper struct {
+	Metadata []*Metadata `json:"metadata"`
+}
+
+// pageInfo is the pagination envelope of custom field listings.
+type pageInfo struct {
+	TotalCount  int `json:"total_count"`
+	CurrentPage int `json:"current_page"`
+	TotalPages  int `json:"total_pages"`
+	PerPage     int `json:"per_page"`
+}
+
+func (p pageInfo) setPageValues(r *Response) {
+	r.FirstPage = 1
+	r.LastPage = p.TotalPages
+	if p.CurrentPage > 1 {
+		r.PrevPage = p.CurrentPage - 1
+	}
+	if p.CurrentPage < p.TotalPages {
+		r.NextPage = p.CurrentPage + 1
+	}
+}
+
+// MetafieldListOptions specifies the optional parameters to the
+// CustomFieldsService.ListMetafields method.
+type MetafieldListOptions struct {
+	// Name limits the results to the metafield with the given name.
+	Name string `url:"name,omitempty"`
+
+	ListOptions
+}
+
+type CustomFieldsService service
+
+// CreateMetafields defines new custom fields for a resource type.
+//
+// Chargify API docs: https://reference.chargify.com/v1/custom-fields-metafields/create-metafields
+func (s *CustomFieldsService) CreateMetafields(ctx context.Context, resourceType ResourceType, metafields []*Metafield) ([]*Metafield, *Response, error) {
+	ctx = withOperationName(ctx, "CustomFields.CreateMetafields")
+
+	u := fmt.Sprintf("%s/metafields", resourceType)
+	req, err := s.client.NewRequest("POST", u, metafieldsWrapper{metafields})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var created []*Metafield
+	resp, err := s.client.Do(ctx, req, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return created, resp, nil
+}
+
+// ListMetafields fetches the custom field definitions for a resource type.
+//
+// Chargify API docs: https://reference.chargify.com/v1/custom-fields-metafields/list-metafields
+func (s *CustomFieldsService) ListMetafields(ctx context.Context, resourceType ResourceType, opt *MetafieldListOptions) ([]*Metafield, *Response, error) {
+	ctx = withOperationName(ctx, "CustomFields.ListMetafields")
+
+	u, err := addOptions(fmt.Sprintf("%s/metafields", resourceType), opt)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	page := new(struct {
+		pageInfo
+		metafieldsWrapper
+	})
+	resp, err := s.client.Do(ctx, req, page)
+	if err != nil {
+		return nil, resp, err
+	}
+	page.setPageValues(resp)
+
+	return page.Metafields, resp, nil
+}
+
+// UpdateMetafield changes a custom field definition. To rename a field, set
+// CurrentName to its existing name and Name to the new one.
+//
+// Chargify API docs: https://reference.chargify.com/v1/custom-fields-metafields/update-metafield
+func (s *CustomFieldsService) UpdateMetafield(ctx context.Context, resourceType ResourceType, metafield *Metafield) ([]*Metafield, *Response, error) {
+	ctx = withOperationName(ctx, "CustomFields.UpdateMetafield")
+
+	u := fmt.Sprintf("%s/metafields", resourceType)
+	req, err := s.client.NewRequest("PUT", u, struct {
+		Metafields *Metafield `json:"metafields"`
+	}{metafield})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var updated []*Metafield
+	resp, err := s.client.Do(ctx, req, &updated)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return updated, resp, nil
+}
+
+// DeleteMetafield removes a custom field definition, along with its values on
+// every resource.
+//
+// Chargify API docs: https://reference.chargify.com/v1/custom-fields-metafields/delete-metafield
+func (s *CustomFieldsService) DeleteMetafield(ctx context.Context, resourceType ResourceType, name string) (*Response, error) {
+	ctx = withOperationName(ctx, "CustomFields.DeleteMetafield")
+
+	u, err := addOptions(fmt.Sprintf("%s/metafields", resourceType), &struct {
+		Name string `url:"name"`
+	}{name})
+	if err != nil {
+		return nil, err
+	}
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// ListMetadata fetches the custom field values of a resource.
+//
+// Chargify API docs: https://reference.chargify.com/v1/custom-fields-metadata/list-metadata
+func (s *CustomFieldsService) ListMetadata(ctx context.Context, resourceType ResourceType, resourceID int, opt *ListOptions) ([]*Metadata, *Response, error) {
+	ctx = withOperationName(ctx, "CustomFields.ListMetadata")
+
+	u, err := addOptions(fmt.Sprintf("%s/%d/metadata", resourceType, resourceID), opt)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	page := new(struct {
+		pageInfo
+		metadataWrapper
+	})
+	resp, err := s.client.Do(ctx, req, page)
+	if err != nil {
+		return nil, resp, err
+	}
+	page.setPageValues(resp)
+
+	return page.Metadata, resp, nil
+}
+
+// UpsertMetadata sets the given custom field values on a resource in a single
+// request, creating values that do not exist yet and overwriting those that
+// do.
+//
+// Chargify API docs: https://reference.chargify.com/v1/custom-fields-metadata/create-metadata
+func (s *CustomFieldsService) UpsertMetadata(ctx context.Context, resourceType ResourceType, resourceID int, metadata []*Metadata) ([]*Metadata, *Response, error) {
+	ctx = withOperationName(ctx, "CustomFields.UpsertMetadata")
+
+	u := fmt.Sprintf("%s/%d/metadata", resourceType, resourceID)
+	req, err := s.client.NewRequest("POST", u, metadataWrapper{metadata})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var saved []*Metadata
+	resp, err := s.client.Do(ctx, req, &saved)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return saved, resp, nil
+}
+
+// SetMetadata sets a single custom field value on a resource.
+func (s *CustomFieldsService) SetMetadata(ctx context.Context, resourceType ResourceType, resourceID int, name, value string) (*Metadata, *Response, error) {
+	ctx = withOperationName(ctx, "CustomFields.SetMetadata")
+
+	saved, resp, err := s.UpsertMetadata(ctx, resourceType, resourceID, []*Metadata{{Name: name, Value: value}})
+	if err != nil || len(saved) == 0 {
+		return nil, resp, err
+	}
+	return saved[0], resp, nil
+}
+
+// DeleteMetadata removes the named custom field values from a resource.
+//
+// Chargify API docs: https://reference.chargify.com/v1/custom-fields-metadata/delete-metadata
+func (s *CustomFieldsService) DeleteMetadata(ctx context.Context, resourceType ResourceType, resourceID int, names ...string) (*Response, error) {
+	ctx = withOperationName(ctx, "CustomFields.DeleteMetadata")
+
+	u, err := addOptions(fmt.Sprintf("%s/%d/metadata", resourceType, resourceID), &struct {
+		Names []string `url:"names[]"`
+	}{names})
+	if err != nil {
+		return nil, err
+	}
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
@@ -0,0 +1,203 @@
+package chargify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestCustomFieldsService_CreateMetafields(t *testing.T) {
+	setup()
+	defer teardown()
+
+	input := []*Metafield{{Name: "Plan tier", InputType: MetafieldDropdown, Enum: []string{"gold", "silver"}}}
+
+	mux.HandleFunc("/subscriptions/metafields", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		v := new(metafieldsWrapper)
+		json.NewDecoder(r.Body).Decode(v)
+		if !reflect.DeepEqual(v.Metafields, input) {
+			t.Errorf("Request body = %+v, want %+v", v.Metafields, input)
+		}
+		fmt.Fprint(w, `[{"name":"Plan tier","input_type":"dropdown","enum":["gold","silver"]}]`)
+	})
+
+	metafields, _, err := client.CustomFields.CreateMetafields(context.Background(), ResourceSubscriptions, input)
+	if err != nil {
+		t.Errorf("CustomFields.CreateMetafields returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(metafields, input) {
+		t.Errorf("CustomFields.CreateMetafields returned %+v, want %+v", metafields, input)
+	}
+}
+
+func TestCustomFieldsService_ListMetafields(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers/metafields", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{
+			"total_count": 3,
+			"current_page": 1,
+			"total_pages": 2,
+			"per_page": 2,
+			"metafields": [{"id":1,"name":"Account id","input_type":"text"},{"id":2,"name":"Region","input_type":"radio","enum":["eu","us"]}]
+		}`)
+	})
+
+	metafields, resp, err := client.CustomFields.ListMetafields(context.Background(), ResourceCustomers, nil)
+	if err != nil {
+		t.Errorf("CustomFields.ListMetafields returned error: %v", err)
+	}
+
+	want := []*Metafield{
+		{Id: 1, Name: "Account id", InputType: MetafieldText},
+		{Id: 2, Name: "Region", InputType: MetafieldRadio, Enum: []string{"eu", "us"}},
+	}
+	if !reflect.DeepEqual(metafields, want) {
+		t.Errorf("CustomFields.ListMetafields returned %+v, want %+v", metafields, want)
+	}
+	if resp.NextPage != 2 || resp.LastPage != 2 {
+		t.Errorf("CustomFields.ListMetafields page values are next %d last %d, want 2 and 2", resp.NextPage, resp.LastPage)
+	}
+}
+
+func TestCustomFieldsService_UpdateMetafield(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers/metafields", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		var v map[string]*Metafield
+		json.NewDecoder(r.Body).Decode(&v)
+		if want := (&Metafield{Name: "Account number", CurrentName: "Account id"}); !reflect.DeepEqual(v["metafields"], want) {
+			t.Errorf("Request body = %+v, want %+v", v["metafields"], want)
+		}
+		fmt.Fprint(w, `[{"name":"Account number","input_type":"text"}]`)
+	})
+
+	metafields, _, err := client.CustomFields.UpdateMetafield(context.Background(), ResourceCustomers, &Metafield{Name: "Account number", CurrentName: "Account id"})
+	if err != nil {
+		t.Errorf("CustomFields.UpdateMetafield returned error: %v", err)
+	}
+
+	want := []*Metafield{{Name: "Account number", InputType: MetafieldText}}
+	if !reflect.DeepEqual(metafields, want) {
+		t.Errorf("CustomFields.UpdateMetafield returned %+v, want %+v", metafields, want)
+	}
+}
+
+func TestCustomFieldsService_DeleteMetafield(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers/metafields", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		if got, want := r.URL.Query().Get("name"), "Account id"; got != want {
+			t.Errorf("CustomFields.DeleteMetafield name is %q, want %q", got, want)
+		}
+	})
+
+	if _, err := client.CustomFields.DeleteMetafield(context.Background(), ResourceCustomers, "Account id"); err != nil {
+		t.Errorf("CustomFields.DeleteMetafield returned error: %v", err)
+	}
+}
+
+func TestCustomFieldsService_ListMetadata(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/14900541/metadata", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{
+			"total_count": 1,
+			"current_page": 1,
+			"total_pages": 1,
+			"per_page": 20,
+			"metadata": [{"id":7,"name":"Account id","value":"A-123","resource_id":14900541}]
+		}`)
+	})
+
+	metadata, resp, err := client.CustomFields.ListMetadata(context.Background(), ResourceSubscriptions, 14900541, nil)
+	if err != nil {
+		t.Errorf("CustomFields.ListMetadata returned error: %v", err)
+	}
+
+	want := []*Metadata{{Id: 7, Name: "Account id", Value: "A-123", ResourceId: 14900541}}
+	if !reflect.DeepEqual(metadata, want) {
+		t.Errorf("CustomFields.ListMetadata returned %+v, want %+v", metadata, want)
+	}
+	if resp.NextPage != 0 {
+		t.Errorf("CustomFields.ListMetadata NextPage is %d, want 0", resp.NextPage)
+	}
+}
+
+func TestCustomFieldsService_UpsertMetadata(t *testing.T) {
+	setup()
+	defer teardown()
+
+	input := []*Metadata{{Name: "Account id", Value: "A-123"}, {Name: "Beta", Value: "true"}}
+
+	mux.HandleFunc("/customers/14399371/metadata", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		v := new(metadataWrapper)
+		json.NewDecoder(r.Body).Decode(v)
+		if !reflect.DeepEqual(v.Metadata, input) {
+			t.Errorf("Request body = %+v, want %+v", v.Metadata, input)
+		}
+		fmt.Fprint(w, `[{"name":"Account id","value":"A-123","resource_id":14399371},{"name":"Beta","value":"true","resource_id":14399371}]`)
+	})
+
+	metadata, _, err := client.CustomFields.UpsertMetadata(context.Background(), ResourceCustomers, 14399371, input)
+	if err != nil {
+		t.Errorf("CustomFields.UpsertMetadata returned error: %v", err)
+	}
+
+	want := []*Metadata{
+		{Name: "Account id", Value: "A-123", ResourceId: 14399371},
+		{Name: "Beta", Value: "true", ResourceId: 14399371},
+	}
+	if !reflect.DeepEqual(metadata, want) {
+		t.Errorf("CustomFields.UpsertMetadata returned %+v, want %+v", metadata, want)
+	}
+}
+
+func TestCustomFieldsService_UpsertMetadata_emptyValue(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/1/metadata", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), `{"metadata":[{"name":"Beta","value":""}]}`+"\n"; got != want {
+			t.Errorf("Request body = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `[]`)
+	})
+
+	input := []*Metadata{{Name: "Beta"}}
+	if _, _, err := client.CustomFields.UpsertMetadata(context.Background(), ResourceSubscriptions, 1, input); err != nil {
+		t.Errorf("CustomFields.UpsertMetadata returned error: %v", err)
+	}
+}
+
+func TestCustomFieldsService_DeleteMetadata(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers/14399371/metadata", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		if got, want := r.URL.Query()["names[]"], []string{"Account id", "Beta"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("CustomFields.DeleteMetadata names are %v, want %v", got, want)
+		}
+	})
+
+	if _, err := client.CustomFields.DeleteMetadata(context.Background(), ResourceCustomers, 14399371, "Account id", "Beta"); err != nil {
+		t.Errorf("CustomFields.DeleteMetadata returned error: %v", err)
+	}
+}
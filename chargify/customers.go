@@ -1,5 +1,16 @@
 package chargify
 
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+type CustomerWrapper struct {
+	Customer *Customer `json:"customer"`
+}
+
 type Customer struct {
 	Id                         int            `json:"id,omitempty"`
 	FirstName                  string         `json:"first_name,omitempty"`
@@ -22,25 +33,152 @@ type Customer struct {
 	PortalCustomerCreatedAt    *FormattedTime `json:"portal_customer_created_at,omitempty"`
 	CcEmails                   string         `json:"cc_emails,omitempty"`
 	TaxExempt                  bool           `json:"tax_exempt,omitempty"`
+
+	// Extra holds the fields of the API response with no field above.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type CreditCard struct {
-	Id                 int    `json:"id,omitempty"`
-	FirstName          string `json:"first_name,omitempty"`
-	LastName           string `json:"last_name,omitempty"`
-	MaskedCardNumber   string `json:"masked_card_number,omitempty"`
-	CardType           string `json:"card_type,omitempty"`
-	ExpirationMonth    int    `json:"expiration_month,omitempty"`
-	ExpirationYear     int    `json:"expiration_year,omitempty"`
-	CustomerId         int    `json:"customer_id,omitempty"`
-	CurrentVault       string `json:"current_vault,omitempty"`
-	VaultToken         string `json:"vault_token,omitempty"`
-	BillingAddress     string `json:"billing_address,omitempty"`
-	BillingCity        string `json:"billing_city,omitempty"`
-	BillingState       string `json:"billing_state,omitempty"`
-	BillingZip         string `json:"billing_zip,omitempty"`
-	BillingCountry     string `json:"billing_country,omitempty"`
-	CustomerVaultToken string `json:"customer_vault_token,omitempty"`
-	BillingAddress2    string `json:"billing_address_2,omitempty"`
-	PaymentType        string `json:"payment_type,omitempty"`
+	Id                 int         `json:"id,omitempty"`
+	FirstName          string      `json:"first_name,omitempty"`
+	LastName           string      `json:"last_name,omitempty"`
+	MaskedCardNumber   string      `json:"masked_card_number,omitempty"`
+	CardType           string      `json:"card_type,omitempty"`
+	ExpirationMonth    int         `json:"expiration_month,omitempty"`
+	ExpirationYear     int         `json:"expiration_year,omitempty"`
+	CustomerId         int         `json:"customer_id,omitempty"`
+	CurrentVault       string      `json:"current_vault,omitempty"`
+	VaultToken         string      `json:"vault_token,omitempty"`
+	BillingAddress     string      `json:"billing_address,omitempty"`
+	BillingCity        string      `json:"billing_city,omitempty"`
+	BillingState       string      `json:"billing_state,omitempty"`
+	BillingZip         string      `json:"billing_zip,omitempty"`
+	BillingCountry     string      `json:"billing_country,omitempty"`
+	CustomerVaultToken string      `json:"customer_vault_token,omitempty"`
+	BillingAddress2    string      `json:"billing_address_2,omitempty"`
+	PaymentType        PaymentType `json:"payment_type,omitempty"`
+
+	// ChargifyToken is a card tokenized by Chargify.js, sent instead of the
+	// card details when creating a payment profile or subscription.
+	ChargifyToken string `json:"chargify_token,omitempty"`
+	// FullNumber and CVV are raw card details. Prefer ChargifyToken, which
+	// keeps them off your servers; see WithTokenizedCardsOnly.
+	FullNumber string `json:"full_number,omitempty"`
+	CVV        string `json:"cvv,omitempty"`
+
+	// Extra holds the fields of the API response with no field above.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type CustomersService service
+
+// Get fetches a customer.
+//
+// Chargify API docs: https://reference.chargify.com/v1/customers/read-the-customer-by-chargify-id
+func (s *CustomersService) Get(ctx context.Context, id int) (*Customer, *Response, error) {
+	ctx = withOperationName(ctx, "Customers.Get")
+	return s.get(ctx, fmt.Sprintf("customers/%d", id))
+}
+
+// Lookup fetches a customer by their reference, the id of the customer in
+// your own system.
+//
+// Chargify API docs: https://reference.chargify.com/v1/customers/read-the-customer-by-reference-value
+func (s *CustomersService) Lookup(ctx context.Context, reference string) (*Customer, *Response, error) {
+	ctx = withOperationName(ctx, "Customers.Lookup")
+	return s.get(ctx, "customers/lookup?reference="+url.QueryEscape(reference))
+}
+
+func (s *CustomersService) get(ctx context.Context, u string) (*Customer, *Response, error) {
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cw := new(CustomerWrapper)
+	resp, err := s.client.Do(ctx, req, cw)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return cw.Customer, resp, nil
+}
+
+// CustomerUpdate holds the changes made to a customer by
+// CustomersService.Update. Unset fields are left unchanged.
+type CustomerUpdate struct {
+	FirstName       Optional[string] `json:"first_name,omitzero"`
+	LastName        Optional[string] `json:"last_name,omitzero"`
+	Email           Optional[string] `json:"email,omitzero"`
+	CcEmails        Optional[string] `json:"cc_emails,omitzero"`
+	Organization    Optional[string] `json:"organization,omitzero"`
+	Reference       Optional[string] `json:"reference,omitzero"`
+	Address         Optional[string] `json:"address,omitzero"`
+	Address2        Optional[string] `json:"address_2,omitzero"`
+	City            Optional[string] `json:"city,omitzero"`
+	State           Optional[string] `json:"state,omitzero"`
+	Zip             Optional[string] `json:"zip,omitzero"`
+	Country         Optional[string] `json:"country,omitzero"`
+	Phone           Optional[string] `json:"phone,omitzero"`
+	Locale          Optional[string] `json:"locale,omitzero"`
+	VatNumber       Optional[string] `json:"vat_number,omitzero"`
+	TaxExempt       Optional[bool]   `json:"tax_exempt,omitzero"`
+	TaxExemptReason Optional[string] `json:"tax_exempt_reason,omitzero"`
+
+	// ParentId is the customer responsible for paying this customer's
+	// invoices. Set it to Null to remove the parent.
+	ParentId Optional[int] `json:"parent_id,omitzero"`
+}
+
+func (u CustomerUpdate) MarshalJSON() ([]byte, error) {
+	return marshalUpdate(u)
+}
+
+// Update changes the details of a customer.
+//
+// Chargify API docs: https://reference.chargify.com/v1/customers/update-customer
+func (s *CustomersService) Update(ctx context.Context, id int, update *CustomerUpdate) (*Customer, *Response, error) {
+	ctx = withOperationName(ctx, "Customers.Update")
+
+	body := struct {
+		Customer *CustomerUpdate `json:"customer"`
+	}{update}
+
+	req, err := s.client.NewRequest("PUT", fmt.Sprintf("customers/%d", id), body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cw := new(CustomerWrapper)
+	resp, err := s.client.Do(ctx, req, cw)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return cw.Customer, resp, nil
+}
+
+// ListSubscriptions fetches the subscriptions of a customer.
+//
+// Chargify API docs: https://reference.chargify.com/v1/customers/list-subscriptions-for-a-customer
+func (s *CustomersService) ListSubscriptions(ctx context.Context, customerID int) ([]*Subscription, *Response, error) {
+	ctx = withOperationName(ctx, "Customers.ListSubscriptions")
+
+	u := fmt.Sprintf("customers/%d/subscriptions", customerID)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var wrappers []*SubscriptionWrapper
+	resp, err := s.client.Do(ctx, req, &wrappers)
+	if err != nil {
+		return nil, resp, err
+	}
+	var subs []*Subscription
+	for _, sw := range wrappers {
+		subs = append(subs, sw.Subscription)
+	}
+	s.client.states.observe(subs...)
+	return subs, resp, nil
 }
@@ -0,0 +1,97 @@
+package chargify
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestCustomersService_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers/14399371", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"customer": {"id":14399371,"reference":"JQPUBLIC"}}`)
+	})
+
+	customer, _, err := client.Customers.Get(context.Background(), 14399371)
+	if err != nil {
+		t.Errorf("Customers.Get returned error: %v", err)
+	}
+
+	want := &Customer{Id: 14399371, Reference: "JQPUBLIC"}
+	if !reflect.DeepEqual(customer, want) {
+		t.Errorf("Customers.Get returned %+v, want %+v", customer, want)
+	}
+}
+
+func TestCustomersService_Lookup(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers/lookup", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("reference"), "JQ PUBLIC"; got != want {
+			t.Errorf("Customers.Lookup reference is %q, want %q", got, want)
+		}
+		fmt.Fprint(w, `{"customer": {"id":14399371,"reference":"JQ PUBLIC"}}`)
+	})
+
+	customer, _, err := client.Customers.Lookup(context.Background(), "JQ PUBLIC")
+	if err != nil {
+		t.Errorf("Customers.Lookup returned error: %v", err)
+	}
+
+	want := &Customer{Id: 14399371, Reference: "JQ PUBLIC"}
+	if !reflect.DeepEqual(customer, want) {
+		t.Errorf("Customers.Lookup returned %+v, want %+v", customer, want)
+	}
+}
+
+func TestCustomersService_ListSubscriptions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers/14399371/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `[{"subscription": {"id":1}},{"subscription": {"id":2}}]`)
+	})
+
+	subs, _, err := client.Customers.ListSubscriptions(context.Background(), 14399371)
+	if err != nil {
+		t.Errorf("Customers.ListSubscriptions returned error: %v", err)
+	}
+
+	want := []*Subscription{{Id: 1}, {Id: 2}}
+	if !reflect.DeepEqual(subs, want) {
+		t.Errorf("Customers.ListSubscriptions returned %+v, want %+v", subs, want)
+	}
+}
+
+func TestCustomersService_Update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/customers/14399371", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), `{"customer":{"cc_emails":"","tax_exempt":false,"parent_id":null}}`+"\n"; got != want {
+			t.Errorf("Request body = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `{"customer": {"id":14399371}}`)
+	})
+
+	update := &CustomerUpdate{CcEmails: Set(""), TaxExempt: Set(false), ParentId: Null[int]()}
+	customer, _, err := client.Customers.Update(context.Background(), 14399371, update)
+	if err != nil {
+		t.Errorf("Customers.Update returned error: %v", err)
+	}
+
+	if want := (&Customer{Id: 14399371}); !reflect.DeepEqual(customer, want) {
+		t.Errorf("Customers.Update returned %+v, want %+v", customer, want)
+	}
+}
@@ -0,0 +1,127 @@
+package chargify
+
+import (
+	"context"
+	"fmt"
+)
+
+type DunnerWrapper struct {
+	Dunner *Dunner `json:"dunner"`
+}
+
+// Dunner is the dunning process of a subscription whose renewal payment
+// failed: the retries of the payment and the reminders sent to the customer
+// until it is paid or the subscription is canceled.
+type Dunner struct {
+	Id                   int            `json:"id,omitempty"`
+	SubscriptionId       int            `json:"subscription_id,omitempty"`
+	RevenueAtRiskInCents int            `json:"revenue_at_risk_in_cents,omitempty"`
+	Attempts             int            `json:"attempts,omitempty"`
+	LastAttemptedAt      *FormattedTime `json:"last_attempted_at,omitempty"`
+	CreatedAt            *FormattedTime `json:"created_at,omitempty"`
+}
+
+// DunningStatus is where a subscription failing to pay is in dunning.
+type DunningStatus struct {
+	SubscriptionId int
+	State          SubscriptionState
+	BalanceInCents int
+	// NextRetryAt is when Chargify next retries the failed payment, which is
+	// the subscription's next assessment.
+	NextRetryAt  *FormattedTime
+	Subscription *Subscription
+}
+
+// dunningStates are the states of subscriptions in dunning.
+var dunningStates = []SubscriptionState{StatePastDue, StateSoftFailure}
+
+// Dunner fetches the dunning process of a subscription.
+//
+// Chargify API docs: https://reference.chargify.com/v1/dunning/read-dunner
+func (s *SubscriptionsService) Dunner(ctx context.Context, id int) (*Dunner, *Response, error) {
+	ctx = withOperationName(ctx, "Subscriptions.Dunner")
+
+	u := fmt.Sprintf("subscriptions/%d/dunner", id)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dw := new(DunnerWrapper)
+	resp, err := s.client.Do(ctx, req, dw)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return dw.Dunner, resp, nil
+}
+
+// Retry retries the failed payment of a subscription in dunning now, rather
+// than at its next scheduled retry.
+//
+// Since the request charges the customer, the client's retry policy only
+// resends it if ctx carries an idempotency key set with WithIdempotencyKey.
+//
+// Chargify API docs: https://reference.chargify.com/v1/subscriptions-retry/retry-subscription
+func (s *SubscriptionsService) Retry(ctx context.Context, id int) (*Subscription, *Response, error) {
+	ctx = withOperationName(ctx, "Subscriptions.Retry")
+
+	if err := s.client.states.check(id, ActionRetry); err != nil {
+		return nil, nil, err
+	}
+
+	if _, ok := IdempotencyKeyFromContext(ctx); !ok {
+		ctx = context.WithValue(ctx, noRetryContextKey{}, true)
+	}
+
+	u := fmt.Sprintf("subscriptions/%d/retry", id)
+	req, err := s.client.NewRequest("PUT", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	swr := new(SubscriptionWrapper)
+	resp, err := s.client.Do(ctx, req, swr)
+	if err != nil {
+		return nil, resp, err
+	}
+	s.client.states.observe(swr.Subscription)
+
+	return swr.Subscription, resp, nil
+}
+
+// ListInDunning fetches the dunning status of every past_due and
+// soft_failure subscription matching opt, going through all pages of
+// results. The State and ListOptions of opt are ignored.
+func (s *SubscriptionsService) ListInDunning(ctx context.Context, opt *SubscriptionListOptions) ([]*DunningStatus, error) {
+	ctx = withOperationName(ctx, "Subscriptions.ListInDunning")
+
+	var o SubscriptionListOptions
+	if opt != nil {
+		o = *opt
+	}
+	o.PerPage = maxPerPage
+
+	var statuses []*DunningStatus
+	for _, state := range dunningStates {
+		o.State = state
+		o.Page = 1
+		for o.Page != 0 {
+			subs, resp, err := s.List(ctx, &o)
+			if err != nil {
+				return nil, err
+			}
+			for _, sub := range subs {
+				statuses = append(statuses, &DunningStatus{
+					SubscriptionId: sub.Id,
+					State:          sub.State,
+					BalanceInCents: sub.BalanceInCents,
+					NextRetryAt:    sub.NextAssessmentAt,
+					Subscription:   sub,
+				})
+			}
+			o.Page = resp.NextPage
+		}
+	}
+	return statuses, nil
+}
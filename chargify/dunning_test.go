@@ -0,0 +1,147 @@
+package chargify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSubscriptionsService_Dunner(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/14900541/dunner", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"dunner": {"id":7,"subscription_id":14900541,"revenue_at_risk_in_cents":2500,"attempts":2,"last_attempted_at":"2024-03-02T10:00:00-05:00"}}`)
+	})
+
+	dunner, _, err := client.Subscriptions.Dunner(context.Background(), 14900541)
+	if err != nil {
+		t.Errorf("Subscriptions.Dunner returned error: %v", err)
+	}
+
+	want := &Dunner{
+		Id:                   7,
+		SubscriptionId:       14900541,
+		RevenueAtRiskInCents: 2500,
+		Attempts:             2,
+		LastAttemptedAt:      NewFormattedTime(`"2024-03-02T10:00:00-05:00"`),
+	}
+	if !reflect.DeepEqual(dunner, want) {
+		t.Errorf("Subscriptions.Dunner returned %+v, want %+v", dunner, want)
+	}
+}
+
+func TestSubscriptionsService_Retry(t *testing.T) {
+	setup()
+	defer teardown()
+	WithTransitionChecks(time.Minute)(client)
+
+	mux.HandleFunc("/subscriptions/14900541/retry", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		fmt.Fprint(w, `{"subscription": {"id":14900541,"state":"active"}}`)
+	})
+
+	sub, _, err := client.Subscriptions.Retry(context.Background(), 14900541)
+	if err != nil {
+		t.Fatalf("Subscriptions.Retry returned error: %v", err)
+	}
+	if sub.Id != 14900541 || sub.State != StateActive {
+		t.Errorf("Subscriptions.Retry returned %+v, want active subscription 14900541", sub)
+	}
+
+	// The subscription is now known to be active, which has nothing to retry.
+	var transitionErr *InvalidTransitionError
+	if _, _, err := client.Subscriptions.Retry(context.Background(), 14900541); !errors.As(err, &transitionErr) {
+		t.Errorf("Subscriptions.Retry of an active subscription returned error %v, want *InvalidTransitionError", err)
+	}
+}
+
+func TestSubscriptionsService_Retry_notResent(t *testing.T) {
+	setup()
+	defer teardown()
+	client.retryPolicy = RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond}
+
+	requests := 0
+	mux.HandleFunc("/subscriptions/14900541/retry", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Error(w, "Bad Gateway", http.StatusBadGateway)
+	})
+
+	if _, _, err := client.Subscriptions.Retry(context.Background(), 14900541); err == nil {
+		t.Errorf("Subscriptions.Retry returned no error, want the 502")
+	}
+	if requests != 1 {
+		t.Errorf("Server received %d payment retries, want 1", requests)
+	}
+
+	requests = 0
+	ctx := WithIdempotencyKey(context.Background(), "retry-14900541")
+	client.Subscriptions.Retry(ctx, 14900541)
+	if requests != 4 {
+		t.Errorf("Server received %d payment retries with an idempotency key, want 4", requests)
+	}
+}
+
+func TestSubscriptionsService_ListInDunning(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var requests []string
+	mux.HandleFunc("/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		q := r.URL.Query()
+		requests = append(requests, q.Get("state")+"/"+q.Get("page"))
+		if q.Get("product") != "3" || q.Get("per_page") != strconv.Itoa(maxPerPage) {
+			t.Errorf("Request query is %v, want product 3 and the largest page size", q)
+		}
+
+		var subs []string
+		switch q.Get("state") + "/" + q.Get("page") {
+		case "past_due/1":
+			for id := 1; id <= maxPerPage; id++ {
+				subs = append(subs, fmt.Sprintf(`{"subscription": {"id":%d,"state":"past_due"}}`, id))
+			}
+		case "past_due/2":
+			subs = append(subs, `{"subscription": {"id":1000,"state":"past_due","balance_in_cents":2500,"next_assessment_at":"2024-03-05T10:00:00-05:00"}}`)
+		case "soft_failure/1":
+			subs = append(subs, `{"subscription": {"id":2000,"state":"soft_failure"}}`)
+		}
+		fmt.Fprint(w, "["+strings.Join(subs, ",")+"]")
+	})
+
+	statuses, err := client.Subscriptions.ListInDunning(context.Background(), &SubscriptionListOptions{
+		State:   StateActive,
+		Product: 3,
+	})
+	if err != nil {
+		t.Fatalf("Subscriptions.ListInDunning returned error: %v", err)
+	}
+
+	if want := []string{"past_due/1", "past_due/2", "soft_failure/1"}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("Subscriptions.ListInDunning requested %v, want %v", requests, want)
+	}
+	if len(statuses) != maxPerPage+2 {
+		t.Fatalf("Subscriptions.ListInDunning returned %d statuses, want %d", len(statuses), maxPerPage+2)
+	}
+	got := statuses[maxPerPage]
+	want := &DunningStatus{
+		SubscriptionId: 1000,
+		State:          StatePastDue,
+		BalanceInCents: 2500,
+		NextRetryAt:    NewFormattedTime(`"2024-03-05T10:00:00-05:00"`),
+		Subscription:   got.Subscription,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Subscriptions.ListInDunning returned %+v, want %+v", got, want)
+	}
+	if last := statuses[maxPerPage+1]; last.SubscriptionId != 2000 || last.State != StateSoftFailure {
+		t.Errorf("Subscriptions.ListInDunning returned %+v last, want soft_failure subscription 2000", last)
+	}
+}
@@ -0,0 +1,106 @@
+package chargify
+
+// The string types in this file name the values Chargify documents for a
+// field. They are plain strings underneath, so values Chargify adds later
+// decode and encode unchanged.
+
+// SubscriptionState is the state of a subscription.
+//
+// Chargify API docs: https://help.chargify.com/subscriptions/subscription-states.html
+type SubscriptionState string
+
+const (
+	StateActive         SubscriptionState = "active"
+	StateAssessing      SubscriptionState = "assessing"
+	StatePending        SubscriptionState = "pending"
+	StateTrialing       SubscriptionState = "trialing"
+	StatePastDue        SubscriptionState = "past_due"
+	StateSoftFailure    SubscriptionState = "soft_failure"
+	StateUnpaid         SubscriptionState = "unpaid"
+	StateCanceled       SubscriptionState = "canceled"
+	StateExpired        SubscriptionState = "expired"
+	StateTrialEnded     SubscriptionState = "trial_ended"
+	StateOnHold         SubscriptionState = "on_hold"
+	StatePaused         SubscriptionState = "paused"
+	StateSuspended      SubscriptionState = "suspended"
+	StateAwaitingSignup SubscriptionState = "awaiting_signup"
+	StateFailedToCreate SubscriptionState = "failed_to_create"
+)
+
+// IsLive reports whether a subscription in state s is in good standing or
+// may still recover without being reactivated, i.e. it is active, being
+// assessed or set up, trialing, or failing payment but still in dunning.
+func (s SubscriptionState) IsLive() bool {
+	switch s {
+	case StateActive, StateAssessing, StatePending, StateTrialing, StatePastDue, StateSoftFailure:
+		return true
+	}
+	return false
+}
+
+// IsDelinquent reports whether a subscription in state s has failed to pay
+// for its current period.
+func (s SubscriptionState) IsDelinquent() bool {
+	switch s {
+	case StatePastDue, StateSoftFailure, StateUnpaid:
+		return true
+	}
+	return false
+}
+
+// IsEnded reports whether a subscription in state s has stopped renewing and
+// has to be reactivated to renew again.
+func (s SubscriptionState) IsEnded() bool {
+	switch s {
+	case StateCanceled, StateExpired, StateTrialEnded, StateUnpaid, StateOnHold, StateSuspended, StateFailedToCreate:
+		return true
+	}
+	return false
+}
+
+// PaymentCollectionMethod is how a subscription's renewals are paid.
+type PaymentCollectionMethod string
+
+const (
+	CollectAutomatically PaymentCollectionMethod = "automatic"
+	CollectByRemittance  PaymentCollectionMethod = "remittance"
+	CollectPrepaid       PaymentCollectionMethod = "prepaid"
+
+	// CollectByInvoice is the former name of CollectByRemittance.
+	CollectByInvoice PaymentCollectionMethod = "invoice"
+)
+
+// CancellationMethod is how a subscription was canceled.
+type CancellationMethod string
+
+const (
+	CanceledByMerchantUI    CancellationMethod = "merchant_ui"
+	CanceledByMerchantAPI   CancellationMethod = "merchant_api"
+	CanceledByDunning       CancellationMethod = "dunning"
+	CanceledInBillingPortal CancellationMethod = "billing_portal"
+	CanceledOnImport        CancellationMethod = "imported"
+	CanceledUnknown         CancellationMethod = "unknown"
+)
+
+// IntervalUnit is the unit of a product's billing, trial or expiration
+// interval.
+type IntervalUnit string
+
+const (
+	IntervalDay   IntervalUnit = "day"
+	IntervalMonth IntervalUnit = "month"
+
+	// IntervalNever is only used for expiration intervals, by products that
+	// do not expire.
+	IntervalNever IntervalUnit = "never"
+)
+
+// PaymentType is the kind of a payment profile.
+type PaymentType string
+
+const (
+	PaymentTypeCreditCard    PaymentType = "credit_card"
+	PaymentTypeBankAccount   PaymentType = "bank_account"
+	PaymentTypePayPalAccount PaymentType = "paypal_account"
+	PaymentTypeApplePay      PaymentType = "apple_pay"
+)
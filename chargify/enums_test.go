@@ -0,0 +1,50 @@
+package chargify
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSubscriptionState_predicates(t *testing.T) {
+	tests := []struct {
+		state                   SubscriptionState
+		live, delinquent, ended bool
+	}{
+		{StateActive, true, false, false},
+		{StateTrialing, true, false, false},
+		{StatePastDue, true, true, false},
+		{StateSoftFailure, true, true, false},
+		{StateUnpaid, false, true, true},
+		{StateCanceled, false, false, true},
+		{StateOnHold, false, false, true},
+		{SubscriptionState("hibernating"), false, false, false},
+	}
+	for _, tt := range tests {
+		if got := tt.state.IsLive(); got != tt.live {
+			t.Errorf("%q.IsLive() is %v, want %v", tt.state, got, tt.live)
+		}
+		if got := tt.state.IsDelinquent(); got != tt.delinquent {
+			t.Errorf("%q.IsDelinquent() is %v, want %v", tt.state, got, tt.delinquent)
+		}
+		if got := tt.state.IsEnded(); got != tt.ended {
+			t.Errorf("%q.IsEnded() is %v, want %v", tt.state, got, tt.ended)
+		}
+	}
+}
+
+func TestSubscription_unknownEnumValues(t *testing.T) {
+	in := `{"state":"hibernating","payment_collection_method":"crypto","cancellation_method":"robot","payment_type":"barter"}`
+
+	sub := new(Subscription)
+	if err := json.Unmarshal([]byte(in), sub); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if sub.State != "hibernating" || sub.PaymentCollectionMethod != "crypto" || sub.CancellationMethod != "robot" || sub.PaymentType != "barter" {
+		t.Errorf("Unmarshal returned %+v, want unknown values kept", sub)
+	}
+
+	out, _ := json.Marshal(sub)
+	if string(out) != in {
+		t.Errorf("Marshal returned %s, want %s", out, in)
+	}
+}
@@ -0,0 +1,196 @@
+package chargify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EventKey identifies the kind of activity an Event records.
+type EventKey string
+
+// Event keys emitted by Chargify.
+//
+// Chargify API docs: https://reference.chargify.com/v1/events/event-keys
+const (
+	EventPaymentSuccess                 EventKey = "payment_success"
+	EventPaymentFailure                 EventKey = "payment_failure"
+	EventSignupSuccess                  EventKey = "signup_success"
+	EventSignupFailure                  EventKey = "signup_failure"
+	EventDelayedSignupCreationSuccess   EventKey = "delayed_signup_creation_success"
+	EventDelayedSignupCreationFailure   EventKey = "delayed_signup_creation_failure"
+	EventBillingDateChange              EventKey = "billing_date_change"
+	EventExpirationDateChange           EventKey = "expiration_date_change"
+	EventRenewalSuccess                 EventKey = "renewal_success"
+	EventRenewalFailure                 EventKey = "renewal_failure"
+	EventSubscriptionStateChange        EventKey = "subscription_state_change"
+	EventSubscriptionProductChange      EventKey = "subscription_product_change"
+	EventPendingCancellationChange      EventKey = "pending_cancellation_change"
+	EventExpiringCard                   EventKey = "expiring_card"
+	EventCustomerUpdate                 EventKey = "customer_update"
+	EventCustomerCreate                 EventKey = "customer_create"
+	EventCustomerDelete                 EventKey = "customer_delete"
+	EventComponentAllocationChange      EventKey = "component_allocation_change"
+	EventMeteredUsage                   EventKey = "metered_usage"
+	EventUpgradeDowngradeSuccess        EventKey = "upgrade_downgrade_success"
+	EventUpgradeDowngradeFailure        EventKey = "upgrade_downgrade_failure"
+	EventStatementClosed                EventKey = "statement_closed"
+	EventStatementSettled               EventKey = "statement_settled"
+	EventSubscriptionCardUpdate         EventKey = "subscription_card_update"
+	EventSubscriptionBankAccountUpdate  EventKey = "subscription_bank_account_update"
+	EventRefundSuccess                  EventKey = "refund_success"
+	EventRefundFailure                  EventKey = "refund_failure"
+	EventUpcomingRenewalNotice          EventKey = "upcoming_renewal_notice"
+	EventTrialEndNotice                 EventKey = "trial_end_notice"
+	EventDunningStepReached             EventKey = "dunning_step_reached"
+	EventInvoiceIssued                  EventKey = "invoice_issued"
+	EventSubscriptionDeletion           EventKey = "subscription_deletion"
+	EventCustomFieldValueChange         EventKey = "custom_field_value_change"
+	EventSubscriptionGroupSignupSuccess EventKey = "subscription_group_signup_success"
+	EventSubscriptionGroupSignupFailure EventKey = "subscription_group_signup_failure"
+)
+
+type EventWrapper struct {
+	Event *Event `json:"event"`
+}
+
+type Event struct {
+	Id                int             `json:"id,omitempty"`
+	Key               EventKey        `json:"key,omitempty"`
+	Message           string          `json:"message,omitempty"`
+	SubscriptionId    int             `json:"subscription_id,omitempty"`
+	CustomerId        int             `json:"customer_id,omitempty"`
+	CreatedAt         *FormattedTime  `json:"created_at,omitempty"`
+	EventSpecificData json.RawMessage `json:"event_specific_data,omitempty"`
+
+	// Extra holds the fields of the API response with no field above.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// SubscriptionStateChange is the event specific data of a
+// subscription_state_change event.
+type SubscriptionStateChange struct {
+	PreviousSubscriptionState SubscriptionState `json:"previous_subscription_state,omitempty"`
+	NewSubscriptionState      SubscriptionState `json:"new_subscription_state,omitempty"`
+}
+
+// SubscriptionProductChange is the event specific data of a
+// subscription_product_change event.
+type SubscriptionProductChange struct {
+	PreviousProductId int `json:"previous_product_id,omitempty"`
+	NewProductId      int `json:"new_product_id,omitempty"`
+}
+
+// DecodeData JSON decodes the event specific data of e into the value pointed
+// to by v. Events without event specific data leave v untouched.
+func (e *Event) DecodeData(v interface{}) error {
+	if len(e.EventSpecificData) == 0 || string(e.EventSpecificData) == "null" {
+		return nil
+	}
+	return json.Unmarshal(e.EventSpecificData, v)
+}
+
+// Data decodes the event specific data of e into the type matching its key.
+// Keys without a dedicated type decode into a map[string]interface{}, and a
+// nil value is returned for events that carry no event specific data.
+func (e *Event) Data() (interface{}, error) {
+	if len(e.EventSpecificData) == 0 || string(e.EventSpecificData) == "null" {
+		return nil, nil
+	}
+	var v interface{}
+	switch e.Key {
+	case EventSubscriptionStateChange:
+		v = new(SubscriptionStateChange)
+	case EventSubscriptionProductChange:
+		v = new(SubscriptionProductChange)
+	default:
+		v = new(map[string]interface{})
+	}
+	if err := e.DecodeData(v); err != nil {
+		return nil, err
+	}
+	if m, ok := v.(*map[string]interface{}); ok {
+		return *m, nil
+	}
+	return v, nil
+}
+
+// EventListOptions specifies the optional parameters to the
+// EventsService.List method.
+type EventListOptions struct {
+	// SinceID returns only events with an id greater than or equal to it.
+	SinceID int `url:"since_id,omitempty"`
+
+	// MaxID returns only events with an id less than or equal to it.
+	MaxID int `url:"max_id,omitempty"`
+
+	// Direction sorts the events by id. Can be one of "asc" or "desc".
+	// Default: "desc".
+	Direction string `url:"direction,omitempty"`
+
+	// Filter limits the results to the given event keys.
+	Filter []EventKey `url:"filter,comma,omitempty"`
+
+	// DateField is the timestamp the date range applies to. Default:
+	// "created_at".
+	DateField string `url:"date_field,omitempty"`
+
+	// StartDate and EndDate restrict the results to events on or between
+	// the given days, evaluated in the site's time zone.
+	StartDate time.Time `url:"start_date,omitempty" layout:"2006-01-02"`
+	EndDate   time.Time `url:"end_date,omitempty" layout:"2006-01-02"`
+
+	// StartDatetime and EndDatetime restrict the results to events on or
+	// between the given instants. They take precedence over StartDate and
+	// EndDate.
+	StartDatetime time.Time `url:"start_datetime,omitempty"`
+	EndDatetime   time.Time `url:"end_datetime,omitempty"`
+
+	ListOptions
+}
+
+type EventsService service
+
+// List fetches the events for the site.
+//
+// Chargify API docs: https://reference.chargify.com/v1/events/list-events
+func (s *EventsService) List(ctx context.Context, opt *EventListOptions) ([]*Event, *Response, error) {
+	ctx = withOperationName(ctx, "Events.List")
+	return s.list(ctx, "events", opt)
+}
+
+// ListForSubscription fetches the events for a subscription.
+//
+// Chargify API docs: https://reference.chargify.com/v1/events/list-subscription-events
+func (s *EventsService) ListForSubscription(ctx context.Context, subscriptionID int, opt *EventListOptions) ([]*Event, *Response, error) {
+	ctx = withOperationName(ctx, "Events.ListForSubscription")
+	return s.list(ctx, fmt.Sprintf("subscriptions/%d/events", subscriptionID), opt)
+}
+
+func (s *EventsService) list(ctx context.Context, u string, opt *EventListOptions) ([]*Event, *Response, error) {
+	u, err := addOptions(u, opt)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var wrappers []*EventWrapper
+	resp, err := s.client.Do(ctx, req, &wrappers)
+	if err != nil {
+		return nil, resp, err
+	}
+	var events []*Event
+	for _, e := range wrappers {
+		events = append(events, e.Event)
+	}
+	if opt != nil {
+		resp.setPageValues(&opt.ListOptions, len(events))
+	} else {
+		resp.setPageValues(nil, len(events))
+	}
+	return events, resp, nil
+}
@@ -0,0 +1,176 @@
+package chargify
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultPollInterval = time.Minute
+
+// CursorStore persists the id of the last event an EventPoller delivered, so
+// that polling resumes after that event when the poller is restarted.
+type CursorStore interface {
+	// LoadCursor returns the id of the last delivered event, or 0 if no
+	// event has been delivered yet.
+	LoadCursor(ctx context.Context) (int, error)
+
+	// SaveCursor records id as the last delivered event.
+	SaveCursor(ctx context.Context, id int) error
+}
+
+// MemoryCursorStore is a CursorStore that keeps the cursor in memory. It is
+// mostly useful in tests and for pollers that should start over on restart.
+type MemoryCursorStore struct {
+	mu sync.Mutex
+	id int
+}
+
+func (m *MemoryCursorStore) LoadCursor(ctx context.Context) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.id, nil
+}
+
+func (m *MemoryCursorStore) SaveCursor(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.id = id
+	return nil
+}
+
+// FileCursorStore is a CursorStore that keeps the cursor in a file at Path.
+// The file is replaced atomically on every save.
+type FileCursorStore struct {
+	Path string
+}
+
+func (f *FileCursorStore) LoadCursor(ctx context.Context) (int, error) {
+	data, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	s := strings.TrimSpace(string(data))
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(s)
+}
+
+func (f *FileCursorStore) SaveCursor(ctx context.Context, id int) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(f.Path), filepath.Base(f.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(strconv.Itoa(id) + "\n"); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.Path)
+}
+
+// EventPoller follows the events feed of a site, delivering every new event
+// in id order. Its position in the feed is kept in a CursorStore, so events
+// are delivered at least once across restarts.
+type EventPoller struct {
+	events *EventsService
+	store  CursorStore
+
+	// Interval is how long to wait before checking for new events once
+	// the feed has been read to the end. Default: one minute.
+	Interval time.Duration
+
+	// PerPage is the number of events fetched per request. Default: 200.
+	PerPage int
+
+	// Filter limits the delivered events to the given keys.
+	Filter []EventKey
+}
+
+// NewEventPoller returns an EventPoller reading from events and keeping its
+// cursor in store. If store is nil, a MemoryCursorStore is used.
+func NewEventPoller(events *EventsService, store CursorStore) *EventPoller {
+	if store == nil {
+		store = new(MemoryCursorStore)
+	}
+	return &EventPoller{events: events, store: store}
+}
+
+// Run polls for events newer than the stored cursor and sends them on out,
+// saving the cursor after each event has been received. Run blocks until ctx
+// is done or an error occurs; it returns ctx.Err() in the former case and the
+// error otherwise. Calling Run again resumes from the stored cursor.
+func (p *EventPoller) Run(ctx context.Context, out chan<- *Event) error {
+	cursor, err := p.store.LoadCursor(ctx)
+	if err != nil {
+		return err
+	}
+
+	interval := p.Interval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	perPage := p.PerPage
+	if perPage <= 0 {
+		perPage = maxPerPage
+	}
+
+	for {
+		// since_id is inclusive, so ask for the events after the cursor.
+		sinceID := 0
+		if cursor > 0 {
+			sinceID = cursor + 1
+		}
+		opt := &EventListOptions{
+			SinceID:     sinceID,
+			Direction:   "asc",
+			Filter:      p.Filter,
+			ListOptions: ListOptions{PerPage: perPage},
+		}
+		events, resp, err := p.events.List(ctx, opt)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		delivered := 0
+		for _, e := range events {
+			if e.Id <= cursor {
+				continue
+			}
+			select {
+			case out <- e:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			if err := p.store.SaveCursor(ctx, e.Id); err != nil {
+				return err
+			}
+			cursor = e.Id
+			delivered++
+		}
+
+		if resp.NextPage != 0 && delivered > 0 {
+			continue
+		}
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
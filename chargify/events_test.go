@@ -0,0 +1,193 @@
+package chargify
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestEventsService_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		want := "direction=asc&end_date=2016-11-30&filter=signup_success%2Cpayment_success&page=2&per_page=2&since_id=10&start_date=2016-11-01"
+		if got := r.URL.RawQuery; got != want {
+			t.Errorf("Events.List query is %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `[{"event": {"id":10,"key":"signup_success"}},{"event": {"id":11,"key":"payment_success"}}]`)
+	})
+
+	opt := &EventListOptions{
+		SinceID:     10,
+		Direction:   "asc",
+		Filter:      []EventKey{EventSignupSuccess, EventPaymentSuccess},
+		StartDate:   time.Date(2016, 11, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:     time.Date(2016, 11, 30, 0, 0, 0, 0, time.UTC),
+		ListOptions: ListOptions{Page: 2, PerPage: 2},
+	}
+	events, resp, err := client.Events.List(context.Background(), opt)
+	if err != nil {
+		t.Errorf("Events.List returned error: %v", err)
+	}
+
+	want := []*Event{{Id: 10, Key: EventSignupSuccess}, {Id: 11, Key: EventPaymentSuccess}}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("Events.List returned %+v, want %+v", events, want)
+	}
+	if resp.NextPage != 3 || resp.PrevPage != 1 {
+		t.Errorf("Events.List page values are next %d prev %d, want 3 and 1", resp.NextPage, resp.PrevPage)
+	}
+}
+
+func TestEventsService_ListForSubscription(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/14900541/events", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `[{"event": {"id":1,"subscription_id":14900541}}]`)
+	})
+
+	events, resp, err := client.Events.ListForSubscription(context.Background(), 14900541, nil)
+	if err != nil {
+		t.Errorf("Events.ListForSubscription returned error: %v", err)
+	}
+
+	want := []*Event{{Id: 1, SubscriptionId: 14900541}}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("Events.ListForSubscription returned %+v, want %+v", events, want)
+	}
+	if resp.NextPage != 0 {
+		t.Errorf("Events.ListForSubscription NextPage is %d, want 0", resp.NextPage)
+	}
+}
+
+func TestEvent_Data(t *testing.T) {
+	e := &Event{
+		Key:               EventSubscriptionStateChange,
+		EventSpecificData: []byte(`{"previous_subscription_state":"trialing","new_subscription_state":"active"}`),
+	}
+	data, err := e.Data()
+	if err != nil {
+		t.Fatalf("Event.Data returned error: %v", err)
+	}
+	want := &SubscriptionStateChange{PreviousSubscriptionState: "trialing", NewSubscriptionState: "active"}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Event.Data returned %+v, want %+v", data, want)
+	}
+
+	e = &Event{Key: EventCustomerUpdate, EventSpecificData: []byte(`null`)}
+	if data, err := e.Data(); data != nil || err != nil {
+		t.Errorf("Event.Data returned %v, %v, want nil, nil", data, err)
+	}
+}
+
+func TestEventPoller_Run(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("since_id") {
+		case "":
+			fmt.Fprint(w, `[{"event": {"id":1}},{"event": {"id":2}}]`)
+		case "3":
+			fmt.Fprint(w, `[{"event": {"id":3}}]`)
+		default:
+			fmt.Fprint(w, `[]`)
+		}
+	})
+
+	store := new(MemoryCursorStore)
+	p := NewEventPoller(client.Events, store)
+	p.PerPage = 2
+	p.Interval = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := make(chan *Event)
+	done := make(chan error)
+	go func() { done <- p.Run(ctx, out) }()
+
+	var ids []int
+	for len(ids) < 3 {
+		ids = append(ids, (<-out).Id)
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("EventPoller.Run returned %v, want %v", err, context.Canceled)
+	}
+
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("EventPoller.Run delivered %v, want %v", ids, want)
+	}
+	if id, _ := store.LoadCursor(context.Background()); id != 3 {
+		t.Errorf("EventPoller.Run saved cursor %d, want 3", id)
+	}
+}
+
+func TestEventPoller_Run_onePerPage(t *testing.T) {
+	setup()
+	defer teardown()
+
+	ids := []int{1, 5, 99}
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		since, _ := strconv.Atoi(r.URL.Query().Get("since_id"))
+		for _, id := range ids {
+			if id >= since {
+				fmt.Fprintf(w, `[{"event": {"id":%d}}]`, id)
+				return
+			}
+		}
+		fmt.Fprint(w, `[]`)
+	})
+
+	p := NewEventPoller(client.Events, nil)
+	p.PerPage = 1
+	p.Interval = time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	out := make(chan *Event)
+	go p.Run(ctx, out)
+
+	var got []int
+	for len(got) < len(ids) {
+		select {
+		case e := <-out:
+			got = append(got, e.Id)
+		case <-ctx.Done():
+			t.Fatalf("EventPoller.Run delivered %v before timing out, want %v", got, ids)
+		}
+	}
+	if !reflect.DeepEqual(got, ids) {
+		t.Errorf("EventPoller.Run delivered %v, want %v", got, ids)
+	}
+}
+
+func TestFileCursorStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chargify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := &FileCursorStore{Path: filepath.Join(dir, "cursor")}
+	ctx := context.Background()
+	if id, err := store.LoadCursor(ctx); id != 0 || err != nil {
+		t.Errorf("FileCursorStore.LoadCursor returned %d, %v, want 0, nil", id, err)
+	}
+	if err := store.SaveCursor(ctx, 42); err != nil {
+		t.Fatalf("FileCursorStore.SaveCursor returned error: %v", err)
+	}
+	if id, err := (&FileCursorStore{Path: store.Path}).LoadCursor(ctx); id != 42 || err != nil {
+		t.Errorf("FileCursorStore.LoadCursor returned %d, %v, want 42, nil", id, err)
+	}
+}
@@ -0,0 +1,277 @@
+package chargify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Resources decoded from the API keep the JSON fields this package does not
+// know about in their Extra field, so that attributes Chargify adds can be
+// read before they get a field of their own:
+//
+//	var tier string
+//	err := json.Unmarshal(sub.Extra["tier"], &tier)
+//
+// Extra is not encoded by json.Marshal, so that a fetched resource can be sent
+// back to the API without its read-only fields. MarshalWithExtra encodes it,
+// e.g. to store a resource and decode it again later.
+
+// knownFields caches the JSON fields of struct types by their lower-cased
+// name, since encoding/json matches keys case-insensitively.
+var knownFields sync.Map // map[reflect.Type]map[string]jsonField
+
+// jsonField is a struct field encoded by encoding/json.
+type jsonField struct {
+	typ   reflect.Type
+	index []int
+	// quoted is set by the ",string" option.
+	quoted bool
+}
+
+// jsonFields returns the JSON fields of struct type t, including those of
+// embedded structs.
+func jsonFields(t reflect.Type) map[string]jsonField {
+	if fields, ok := knownFields.Load(t); ok {
+		return fields.(map[string]jsonField)
+	}
+
+	fields := make(map[string]jsonField)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for name, field := range jsonFields(ft) {
+					field.index = append([]int{i}, field.index...)
+					fields[name] = field
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		quoted := false
+		for _, opt := range strings.Split(opts, ",") {
+			quoted = quoted || opt == "string"
+		}
+		fields[strings.ToLower(name)] = jsonField{typ: f.Type, index: []int{i}, quoted: quoted}
+	}
+
+	knownFields.Store(t, fields)
+	return fields
+}
+
+// unmarshalExtra decodes data into v, a pointer to a struct without an
+// UnmarshalJSON method, and returns the fields of data that v has no field
+// for. Like json.Unmarshal, it decodes every field it can and returns the
+// first error.
+func unmarshalExtra(data []byte, v interface{}) (map[string]json.RawMessage, error) {
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	rv := reflect.ValueOf(v).Elem()
+	known := jsonFields(rv.Type())
+	var extra map[string]json.RawMessage
+	var firstErr error
+	for name, value := range all {
+		field, ok := known[strings.ToLower(name)]
+		if !ok {
+			if extra == nil {
+				extra = make(map[string]json.RawMessage)
+			}
+			extra[name] = value
+			continue
+		}
+		if err := decodeField(value, fieldByIndex(rv, field.index), field.quoted); err != nil && firstErr == nil {
+			if typeErr, ok := err.(*json.UnmarshalTypeError); ok {
+				if typeErr.Field == "" {
+					typeErr.Struct = rv.Type().Name()
+					typeErr.Field = name
+				} else {
+					typeErr.Field = name + "." + typeErr.Field
+				}
+			}
+			firstErr = err
+		}
+	}
+	return extra, firstErr
+}
+
+// decodeField decodes value into the struct field f. Values of fields with
+// the ",string" option are JSON strings holding the encoded value.
+func decodeField(value json.RawMessage, f reflect.Value, quoted bool) error {
+	if quoted && !bytes.Equal(bytes.TrimSpace(value), []byte("null")) {
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return fmt.Errorf("json: invalid use of ,string struct tag, trying to unmarshal %s into %v", value, f.Type())
+		}
+		value = json.RawMessage(s)
+	}
+	return json.Unmarshal(value, f.Addr().Interface())
+}
+
+// fieldByIndex returns the field of struct v with the given index, allocating
+// the embedded structs it is promoted through.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// MarshalWithExtra returns the JSON encoding of v with the Extra fields of the
+// resources it holds added, so that decoding the result gives back what was
+// decoded from the API. Extra fields named like a field of their resource are
+// left out, and the keys of objects holding resources are sorted.
+func MarshalWithExtra(v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return json.Marshal(addExtra(doc, reflect.ValueOf(v)))
+}
+
+// addExtra adds the Extra fields of the resources in v to doc, the decoded
+// JSON encoding of v.
+func addExtra(doc interface{}, v reflect.Value) interface{} {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return doc
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return doc
+		}
+		known := jsonFields(v.Type())
+		for name, value := range obj {
+			if field, ok := known[strings.ToLower(name)]; ok && !field.quoted {
+				obj[name] = addExtra(value, fieldByIndex(v, field.index))
+			}
+		}
+		if !hasExtra(v.Type()) {
+			return obj
+		}
+		for name, value := range v.FieldByName("Extra").Interface().(map[string]json.RawMessage) {
+			if _, ok := known[strings.ToLower(name)]; !ok {
+				obj[name] = value
+			}
+		}
+		return obj
+	case reflect.Slice, reflect.Array:
+		items, ok := doc.([]interface{})
+		if !ok {
+			return doc
+		}
+		for i := range items {
+			items[i] = addExtra(items[i], v.Index(i))
+		}
+		return items
+	case reflect.Map:
+		obj, ok := doc.(map[string]interface{})
+		if !ok || v.Type().Key().Kind() != reflect.String {
+			return doc
+		}
+		for _, key := range v.MapKeys() {
+			if value, ok := obj[key.String()]; ok {
+				obj[key.String()] = addExtra(value, v.MapIndex(key))
+			}
+		}
+		return obj
+	}
+	return doc
+}
+
+// The methods below decode resources through a local copy of their type, which
+// has the same fields but none of the methods, to keep encoding/json from
+// calling them again.
+
+func (s *Subscription) UnmarshalJSON(data []byte) error {
+	type subscription Subscription
+	extra, err := unmarshalExtra(data, (*subscription)(s))
+	s.Extra = extra
+	return err
+}
+
+func (c *Customer) UnmarshalJSON(data []byte) error {
+	type customer Customer
+	extra, err := unmarshalExtra(data, (*customer)(c))
+	c.Extra = extra
+	return err
+}
+
+func (c *CreditCard) UnmarshalJSON(data []byte) error {
+	type creditCard CreditCard
+	extra, err := unmarshalExtra(data, (*creditCard)(c))
+	c.Extra = extra
+	return err
+}
+
+func (p *Product) UnmarshalJSON(data []byte) error {
+	type product Product
+	extra, err := unmarshalExtra(data, (*product)(p))
+	p.Extra = extra
+	return err
+}
+
+func (f *ProductFamily) UnmarshalJSON(data []byte) error {
+	type productFamily ProductFamily
+	extra, err := unmarshalExtra(data, (*productFamily)(f))
+	f.Extra = extra
+	return err
+}
+
+func (t *Transaction) UnmarshalJSON(data []byte) error {
+	type transaction Transaction
+	extra, err := unmarshalExtra(data, (*transaction)(t))
+	t.Extra = extra
+	return err
+}
+
+func (e *Event) UnmarshalJSON(data []byte) error {
+	type event Event
+	extra, err := unmarshalExtra(data, (*event)(e))
+	e.Extra = extra
+	return err
+}
+
+func (g *SubscriptionGroup) UnmarshalJSON(data []byte) error {
+	type subscriptionGroup SubscriptionGroup
+	extra, err := unmarshalExtra(data, (*subscriptionGroup)(g))
+	g.Extra = extra
+	return err
+}
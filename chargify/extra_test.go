@@ -0,0 +1,106 @@
+package chargify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestExtra_unmarshal(t *testing.T) {
+	var sub Subscription
+	data := `{"id":1,"State":"active","tier":"gold","credit_card":{"id":2,"wallet":{"kind":"apple"}}}`
+	if err := json.Unmarshal([]byte(data), &sub); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+
+	if sub.Id != 1 || sub.State != StateActive {
+		t.Errorf("Unmarshal returned %+v, want id 1 and state active", sub)
+	}
+	if want := map[string]json.RawMessage{"tier": json.RawMessage(`"gold"`)}; !reflect.DeepEqual(sub.Extra, want) {
+		t.Errorf("Subscription Extra is %s, want %s", sub.Extra, want)
+	}
+	if want := map[string]json.RawMessage{"wallet": json.RawMessage(`{"kind":"apple"}`)}; !reflect.DeepEqual(sub.CreditCard.Extra, want) {
+		t.Errorf("CreditCard Extra is %s, want %s", sub.CreditCard.Extra, want)
+	}
+
+	var c Customer
+	if err := json.Unmarshal([]byte(`{"id":3,"email":"a@example.com"}`), &c); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if c.Extra != nil {
+		t.Errorf("Customer Extra is %s, want nil", c.Extra)
+	}
+}
+
+func TestExtra_unmarshalErrors(t *testing.T) {
+	var sub Subscription
+	err := json.Unmarshal([]byte(`{"id":"x","state":"active","signup_revenue":"1.5","tier":1}`), &sub)
+	typeErr, ok := err.(*json.UnmarshalTypeError)
+	if !ok || typeErr.Field != "id" {
+		t.Fatalf("Unmarshal returned error %v, want *json.UnmarshalTypeError for id", err)
+	}
+	if sub.State != StateActive || sub.SignupRevenue != 1.5 || string(sub.Extra["tier"]) != "1" {
+		t.Errorf("Unmarshal returned %+v, want the other fields decoded", sub)
+	}
+}
+
+func TestExtra_marshal(t *testing.T) {
+	p := Product{
+		Id: 1,
+		Extra: map[string]json.RawMessage{
+			"z_new": json.RawMessage(`[1,2]`),
+			"a_new": json.RawMessage(`true`),
+			"name":  json.RawMessage(`"shadowed"`),
+		},
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	if want := `{"id":1}`; string(b) != want {
+		t.Errorf("Marshal returned %s, want %s", b, want)
+	}
+
+	b, err = MarshalWithExtra(p)
+	if err != nil {
+		t.Fatalf("MarshalWithExtra returned error: %v", err)
+	}
+	if want := `{"a_new":true,"id":1,"z_new":[1,2]}`; string(b) != want {
+		t.Errorf("MarshalWithExtra returned %s, want %s", b, want)
+	}
+
+	sub := []*Subscription{{
+		Id:         2,
+		CreditCard: &CreditCard{Id: 3, Extra: map[string]json.RawMessage{"wallet": json.RawMessage(`"apple"`)}},
+	}}
+	b, _ = MarshalWithExtra(sub)
+	if want := `[{"credit_card":{"id":3,"wallet":"apple"},"id":2}]`; string(b) != want {
+		t.Errorf("MarshalWithExtra of nested resources returned %s, want %s", b, want)
+	}
+}
+
+func TestExtra_roundTrip(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `[{"event":{"id":1,"key":"signup_success","site_id":9}}]`)
+	})
+
+	events, _, err := client.Events.List(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Events.List returned error: %v", err)
+	}
+	if len(events) != 1 || string(events[0].Extra["site_id"]) != "9" {
+		t.Fatalf("Events.List returned %+v, want site_id in Extra", events)
+	}
+
+	b, _ := MarshalWithExtra(events[0])
+	if want := `{"id":1,"key":"signup_success","site_id":9}`; string(b) != want {
+		t.Errorf("MarshalWithExtra returned %s, want %s", b, want)
+	}
+}
@@ -0,0 +1,24 @@
+package chargify
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Hooks are callbacks a Client invokes around every HTTP request it sends,
+// including retries. Nil hooks are skipped. The Operation of the request can
+// be found in ctx with OperationFromContext.
+type Hooks struct {
+	// BeforeRequest is called before req is sent. It may add headers to
+	// req.
+	BeforeRequest func(ctx context.Context, req *http.Request)
+
+	// AfterResponse is called when req completes, with its response or the
+	// error that prevented one, and how long it took.
+	AfterResponse func(ctx context.Context, req *http.Request, resp *http.Response, err error, elapsed time.Duration)
+
+	// OnError is called once when Do fails, with the error it returns:
+	// a transport error, an *ErrorResponse or a decoding error.
+	OnError func(ctx context.Context, req *http.Request, err error)
+}
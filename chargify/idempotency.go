@@ -0,0 +1,65 @@
+package chargify
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+// idempotencyKeyHeader is the header Chargify reads idempotency keys from.
+// Requests repeated with the same key are only processed once.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a copy of ctx carrying key. Mutating requests
+// sent with the returned context use key as their idempotency key instead of
+// a generated one, so an operation resubmitted by the caller (e.g. after a
+// timeout) is not applied twice.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// IdempotencyKeyFromContext returns the idempotency key carried by ctx, if
+// any.
+func IdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key, ok && key != ""
+}
+
+// NewIdempotencyKey returns a random key suitable for WithIdempotencyKey.
+func NewIdempotencyKey() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	// Format as a version 4 UUID.
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// setIdempotencyKey sets the idempotency key header of a mutating request
+// that does not have one yet, taking the key from ctx or generating it.
+func setIdempotencyKey(ctx context.Context, req *http.Request) {
+	switch req.Method {
+	case "GET", "HEAD", "OPTIONS":
+		return
+	}
+	if req.Header.Get(idempotencyKeyHeader) != "" {
+		return
+	}
+	key, ok := IdempotencyKeyFromContext(ctx)
+	if !ok {
+		key = NewIdempotencyKey()
+	}
+	// req is a shallow copy sharing its header with the caller's request,
+	// which must not keep the key for a later call to reuse.
+	header := req.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	req.Header = header
+	req.Header.Set(idempotencyKeyHeader, key)
+}
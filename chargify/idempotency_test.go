@@ -0,0 +1,69 @@
+package chargify
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestDo_idempotencyKey(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var got []string
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get(idempotencyKeyHeader))
+	})
+
+	ctx := context.Background()
+	req, _ := client.NewRequest("GET", "/", nil)
+	client.Do(ctx, req, nil)
+	req, _ = client.NewRequest("POST", "/", nil)
+	client.Do(ctx, req, nil)
+	req, _ = client.NewRequest("POST", "/", nil)
+	client.Do(WithIdempotencyKey(ctx, "caller-key"), req, nil)
+
+	if len(got) != 3 {
+		t.Fatalf("Server received %d requests, want 3", len(got))
+	}
+	if got[0] != "" {
+		t.Errorf("GET request sent idempotency key %q, want none", got[0])
+	}
+	if len(got[1]) != 36 {
+		t.Errorf("POST request sent idempotency key %q, want a generated UUID", got[1])
+	}
+	if got[2] != "caller-key" {
+		t.Errorf("POST request sent idempotency key %q, want %q", got[2], "caller-key")
+	}
+}
+
+func TestDo_idempotencyKeyReusedRequest(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var got []string
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get(idempotencyKeyHeader))
+	})
+
+	req, _ := client.NewRequest("DELETE", "/", nil)
+	client.Do(context.Background(), req, nil)
+	client.Do(context.Background(), req, nil)
+
+	if key := req.Header.Get(idempotencyKeyHeader); key != "" {
+		t.Errorf("Do set idempotency key %q on the caller's request, want none", key)
+	}
+	if len(got) != 2 || got[0] == "" || got[0] == got[1] {
+		t.Errorf("Server received idempotency keys %q, want a new key for each call", got)
+	}
+}
+
+func TestNewIdempotencyKey(t *testing.T) {
+	a, b := NewIdempotencyKey(), NewIdempotencyKey()
+	if a == b {
+		t.Errorf("NewIdempotencyKey returned %q twice", a)
+	}
+	if len(a) != 36 || a[14] != '4' {
+		t.Errorf("NewIdempotencyKey returned %q, want a version 4 UUID", a)
+	}
+}
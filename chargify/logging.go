@@ -0,0 +1,153 @@
+package chargify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// requestIDHeader is the response header Chargify identifies requests by in
+// its logs and support tickets.
+const requestIDHeader = "X-Request-Id"
+
+// redacted replaces sensitive values in logged headers and bodies.
+const redacted = "[REDACTED]"
+
+// redactedFields are the JSON fields whose values are never logged: card
+// data, payment vault tokens and customer email addresses.
+var redactedFields = map[string]bool{
+	"full_number":          true,
+	"cvv":                  true,
+	"vault_token":          true,
+	"customer_vault_token": true,
+	"chargify_token":       true,
+	"bank_account_number":  true,
+	"bank_routing_number":  true,
+	"email":                true,
+	"cc_emails":            true,
+}
+
+// WithDumpBodies makes the client log the headers and bodies of requests and
+// responses along with every request it logs. Card numbers and CVVs, vault
+// tokens, customer emails and the API key are redacted, but bodies may still
+// hold other personal data, so this is meant for debugging only. It has no
+// effect unless a logger is set with WithLogger and enabled at debug level.
+func WithDumpBodies() Option {
+	return func(c *Client) error {
+		c.dumpBodies = true
+		return nil
+	}
+}
+
+// logEnabled reports whether the client logs requests sent with ctx.
+func (c *Client) logEnabled(ctx context.Context) bool {
+	return c.logger != nil && c.logger.Enabled(ctx, slog.LevelDebug)
+}
+
+// logRoundTrip logs a request sent once and its outcome at debug level. If
+// bodies are dumped, the response body is read and replaced so the caller can
+// still decode it.
+func (c *Client) logRoundTrip(ctx context.Context, req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+	}
+	if op := OperationFromContext(ctx); op != nil {
+		attrs = append(attrs, slog.String("operation", op.Name), slog.Int("attempt", op.Retries+1))
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+		if id := resp.Header.Get(requestIDHeader); id != "" {
+			attrs = append(attrs, slog.String("request_id", id))
+		}
+	}
+	attrs = append(attrs, slog.Duration("latency", elapsed))
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	if c.dumpBodies {
+		var body []byte
+		if req.GetBody != nil {
+			if rc, err := req.GetBody(); err == nil {
+				body, _ = ioutil.ReadAll(rc)
+				rc.Close()
+			}
+		}
+		attrs = append(attrs, slog.Group("request",
+			slog.Any("header", redactHeader(req.Header)),
+			slog.String("body", redactBody(body))))
+
+		if resp != nil {
+			body, _ := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+			attrs = append(attrs, slog.Group("response",
+				slog.Any("header", redactHeader(resp.Header)),
+				slog.String("body", redactBody(body))))
+		}
+	}
+
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "chargify: request", attrs...)
+}
+
+// redactHeader returns a copy of h without credentials.
+func redactHeader(h http.Header) http.Header {
+	h = h.Clone()
+	for _, k := range []string{"Authorization", "Cookie", "Set-Cookie"} {
+		if _, ok := h[k]; ok {
+			h.Set(k, redacted)
+		}
+	}
+	return h
+}
+
+// redactBody returns a JSON body with the values of redactedFields replaced.
+// Bodies that are not JSON cannot be inspected and are left out.
+func redactBody(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil || hasMore(d) {
+		return "[non-JSON body omitted]"
+	}
+	b, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return "[non-JSON body omitted]"
+	}
+	return string(b)
+}
+
+// hasMore reports whether d holds input past the value it decoded.
+func hasMore(d *json.Decoder) bool {
+	_, err := d.Token()
+	return err != io.EOF
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			if redactedFields[k] {
+				if e != nil {
+					v[k] = redacted
+				}
+				continue
+			}
+			v[k] = redactValue(e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = redactValue(e)
+		}
+	}
+	return v
+}
@@ -0,0 +1,124 @@
+package chargify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDo_logging(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-abc")
+		fmt.Fprint(w, `{"subscription": {"id":1}}`)
+	})
+
+	var logs bytes.Buffer
+	client.logger = slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	if _, _, err := client.Subscriptions.Get(context.Background(), 1); err != nil {
+		t.Fatalf("Subscriptions.Get returned error: %v", err)
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+		t.Fatalf("Logger received %q, want one JSON entry", logs.String())
+	}
+	for key, want := range map[string]interface{}{
+		"msg":        "chargify: request",
+		"method":     "GET",
+		"path":       "/subscriptions/1",
+		"operation":  "Subscriptions.Get",
+		"status":     float64(200),
+		"request_id": "req-abc",
+	} {
+		if entry[key] != want {
+			t.Errorf("Log entry %s is %v, want %v", key, entry[key], want)
+		}
+	}
+	if _, ok := entry["latency"]; !ok {
+		t.Errorf("Log entry %v has no latency", entry)
+	}
+	if _, ok := entry["request"]; ok {
+		t.Errorf("Log entry %v has a request dump without WithDumpBodies", entry)
+	}
+}
+
+func TestDo_loggingDisabled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {})
+
+	var logs bytes.Buffer
+	client.logger = slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	req, _ := client.NewRequest("GET", "/", nil)
+	client.Do(context.Background(), req, nil)
+
+	if logs.Len() != 0 {
+		t.Errorf("Logger received %q above debug level, want nothing", logs.String())
+	}
+}
+
+func TestDo_dumpBodies(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"subscription": {"id":1,"customer":{"email":"martha@example.com"},"credit_card":{"masked_card_number":"XXXX-XXXX-XXXX-1","vault_token":"tok_1"}}}`)
+	})
+
+	var logs bytes.Buffer
+	client.logger = slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client.dumpBodies = true
+	client.ApiKey = "secret-api-key"
+
+	sub := &Subscription{
+		ProductHandle: "basic",
+		Customer:      &Customer{FirstName: "Martha", Email: "martha@example.com", CcEmails: "boss@example.com"},
+		CreditCard:    &CreditCard{VaultToken: "tok_2", CustomerVaultToken: "cus_2"},
+	}
+	got, _, err := client.Subscriptions.Create(context.Background(), sub)
+	if err != nil {
+		t.Fatalf("Subscriptions.Create returned error: %v", err)
+	}
+	if got.Id != 1 || got.Customer.Email != "martha@example.com" {
+		t.Errorf("Subscriptions.Create returned %+v, want the undumped response", got)
+	}
+
+	out := logs.String()
+	for _, secret := range []string{"secret-api-key", "c2VjcmV0LWFwaS1rZXk6WA", "martha@example.com", "boss@example.com", "tok_1", "tok_2", "cus_2"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("Logger received %q, which contains %q", out, secret)
+		}
+	}
+	for _, kept := range []string{"Martha", "basic", "XXXX-XXXX-XXXX-1", "[REDACTED]"} {
+		if !strings.Contains(out, kept) {
+			t.Errorf("Logger received %q, want it to contain %q", out, kept)
+		}
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	for body, want := range map[string]string{
+		``:                ``,
+		`{"email":null}`:  `{"email":null}`,
+		`[{"cvv":"123"}]`: `[{"cvv":"[REDACTED]"}]`,
+		`{"credit_card_attributes":{"full_number":"4111111111111111","last_name":"Doe"}}`: `{"credit_card_attributes":{"full_number":"[REDACTED]","last_name":"Doe"}}`,
+		`{"amount":10.50}`:    `{"amount":10.50}`,
+		`id,amount\n1,10.50`:  `[non-JSON body omitted]`,
+		`{"a":1} {"email":1}`: `[non-JSON body omitted]`,
+	} {
+		if got := redactBody([]byte(body)); got != want {
+			t.Errorf("redactBody(%q) is %q, want %q", body, got, want)
+		}
+	}
+}
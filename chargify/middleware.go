@@ -0,0 +1,97 @@
+package chargify
+
+import (
+	"context"
+	"net/http"
+)
+
+// Operation describes the API operation a request sent by Do belongs to.
+// Middleware and hooks find it in the request context with
+// OperationFromContext.
+type Operation struct {
+	// Name identifies the operation, e.g. "Subscriptions.Create". Requests
+	// issued by a service method are named after the method called, even
+	// if it calls other methods to do its work; requests sent directly
+	// through Do are named after their method and path. Names set with
+	// WithOperation take precedence over both.
+	Name string
+
+	// Retries is how many times the request has been retried so far.
+	Retries int
+
+	// Cached reports whether the response was served from the client's
+	// cache.
+	Cached bool
+}
+
+type operationContextKey struct{}
+
+// WithOperation returns a copy of ctx that names requests sent with it,
+// overriding the name of the calling service method.
+func WithOperation(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, operationContextKey{}, &Operation{Name: name})
+}
+
+// OperationFromContext returns the operation of the request ctx belongs to,
+// or nil outside of Do.
+func OperationFromContext(ctx context.Context) *Operation {
+	op, _ := ctx.Value(operationContextKey{}).(*Operation)
+	return op
+}
+
+// A Handler sends an API request and returns its response.
+type Handler func(ctx context.Context, req *http.Request) (*http.Response, error)
+
+// A Middleware wraps the Handler a Client sends requests with, to add
+// behavior such as tracing or logging to every API call. The handler passed
+// to the innermost middleware sends the request, retrying it as configured,
+// so middleware observes each operation once. Middleware that consumes the
+// response body must replace it for the client to decode.
+type Middleware func(next Handler) Handler
+
+// WithMiddleware appends middleware to the client's chain. The first
+// middleware is the outermost, i.e. it sees requests first and responses
+// last.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *Client) error {
+		c.middleware = append(c.middleware, middleware...)
+		return nil
+	}
+}
+
+// handler returns the client's send method wrapped in its cache and
+// middleware.
+func (c *Client) handler() Handler {
+	h := Handler(c.send)
+	if c.cache != nil {
+		h = c.cacheHandler(h)
+	}
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		h = c.middleware[i](h)
+	}
+	return h
+}
+
+type operationNameContextKey struct{}
+
+// withOperationName returns a copy of ctx naming the requests sent with it
+// after the service method name, such as "Subscriptions.Create". Service
+// methods call it first thing; a name already in ctx is kept, so that
+// requests sent by a method through another are named after the outer one.
+func withOperationName(ctx context.Context, name string) context.Context {
+	if _, ok := ctx.Value(operationNameContextKey{}).(string); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, operationNameContextKey{}, name)
+}
+
+// newOperation returns the operation for req sent with ctx.
+func newOperation(ctx context.Context, req *http.Request) *Operation {
+	if op := OperationFromContext(ctx); op != nil {
+		return &Operation{Name: op.Name}
+	}
+	if name, ok := ctx.Value(operationNameContextKey{}).(string); ok {
+		return &Operation{Name: name}
+	}
+	return &Operation{Name: req.Method + " " + req.URL.Path}
+}
@@ -0,0 +1,142 @@
+package chargify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// recordOperations returns a middleware appending the name of every operation
+// it sees to names.
+func recordOperations(names *[]string) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *http.Request) (*http.Response, error) {
+			*names = append(*names, OperationFromContext(ctx).Name)
+			return next(ctx, req)
+		}
+	}
+}
+
+func TestDo_operationNames(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+	mux.HandleFunc("/subscriptions/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"subscription": {"id":1}}`)
+	})
+
+	var names []string
+	client.middleware = []Middleware{recordOperations(&names)}
+
+	ctx := context.Background()
+	client.Subscriptions.Get(ctx, 1)
+	client.Events.List(ctx, nil)
+	client.Transactions.Iter(nil).Next(ctx)
+	client.ProformaInvoices.Create(ctx, 1)
+	client.CustomFields.SetMetadata(ctx, ResourceSubscriptions, 1, "tier", "gold")
+	req, _ := client.NewRequest("GET", "products", nil)
+	client.Do(ctx, req, nil)
+	req, _ = client.NewRequest("GET", "products", nil)
+	client.Do(WithOperation(ctx, "Catalog.Refresh"), req, nil)
+
+	want := []string{
+		"Subscriptions.Get", "Events.List", "Transactions.List", "ProformaInvoices.Create",
+		"CustomFields.SetMetadata", "GET /products", "Catalog.Refresh",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Middleware saw operations %q, want %q", names, want)
+	}
+}
+
+func TestDo_middlewareOrder(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header["X-Trace"], []string{"outer", "inner"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("X-Trace headers are %q, want %q", got, want)
+		}
+	})
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, req *http.Request) (*http.Response, error) {
+				req.Header.Add("X-Trace", name)
+				resp, err := next(ctx, req)
+				order = append(order, name)
+				return resp, err
+			}
+		}
+	}
+	client.middleware = []Middleware{trace("outer"), trace("inner")}
+
+	req, _ := client.NewRequest("GET", "/", nil)
+	if _, err := client.Do(context.Background(), req, nil); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	if want := []string{"inner", "outer"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("Middleware saw responses in order %q, want %q", order, want)
+	}
+}
+
+func TestDo_middlewareSeesRetries(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requests := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+		}
+	})
+
+	var retries, calls int
+	client.retryPolicy = RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond}
+	client.middleware = []Middleware{func(next Handler) Handler {
+		return func(ctx context.Context, req *http.Request) (*http.Response, error) {
+			calls++
+			resp, err := next(ctx, req)
+			retries = OperationFromContext(ctx).Retries
+			return resp, err
+		}
+	}}
+
+	req, _ := client.NewRequest("GET", "/", nil)
+	if _, err := client.Do(context.Background(), req, nil); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	if calls != 1 || retries != 1 {
+		t.Errorf("Middleware called %d times with %d retries, want 1 call with 1 retry", calls, retries)
+	}
+}
+
+func TestDo_onErrorHook(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/1", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+
+	var op string
+	var got error
+	client.hooks.OnError = func(ctx context.Context, req *http.Request, err error) {
+		op, got = OperationFromContext(ctx).Name, err
+	}
+
+	_, _, err := client.Subscriptions.Get(context.Background(), 1)
+	if err == nil || got != err {
+		t.Errorf("OnError received %v, want %v", got, err)
+	}
+	if op != "Subscriptions.Get" {
+		t.Errorf("OnError received operation %q, want %q", op, "Subscriptions.Get")
+	}
+}
@@ -0,0 +1,144 @@
+package chargify
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// Optional is a field of an update request that distinguishes leaving a
+// value unchanged from setting it to its zero value or to null. The zero
+// value of an Optional is unset; use Set and Null to make others.
+//
+// The update payloads holding Optional fields leave unset fields out of
+// request bodies, whatever Go version the omitzero tags are compiled with:
+//
+//	update := &SubscriptionUpdate{ReceivesInvoiceEmails: Set(false)}
+type Optional[T any] struct {
+	value T
+	set   bool
+	null  bool
+}
+
+// Set returns an Optional holding v, which is sent even if it is the zero
+// value of T.
+func Set[T any](v T) Optional[T] {
+	return Optional[T]{value: v, set: true}
+}
+
+// Null returns an Optional that is sent as null, to clear the field.
+func Null[T any]() Optional[T] {
+	return Optional[T]{set: true, null: true}
+}
+
+// Get returns the value of o, and whether it holds one. It returns false for
+// unset and null Optionals.
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.set && !o.null
+}
+
+// IsNull reports whether o is set to null.
+func (o Optional[T]) IsNull() bool {
+	return o.null
+}
+
+// IsZero reports whether o is unset. It makes the omitzero JSON option leave
+// out unset fields.
+func (o Optional[T]) IsZero() bool {
+	return !o.set
+}
+
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if o.null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.value)
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*o = Null[T]()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*o = Set(v)
+	return nil
+}
+
+// zeroer is implemented by Optional.
+type zeroer interface {
+	IsZero() bool
+}
+
+// marshalUpdate JSON encodes v, an update payload struct, leaving out the
+// fields tagged omitzero whose IsZero method reports true, such as unset
+// Optionals, and the empty fields tagged omitempty. encoding/json only
+// honours omitzero from Go 1.24 on, and earlier versions would send every
+// unset Optional as its zero value, wiping the field.
+func marshalUpdate(v interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	rt := rv.Type()
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		tag := f.Tag.Get("json")
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+
+		fv := rv.Field(i)
+		if hasOption(opts, "omitzero") && !(fv.Kind() == reflect.Ptr && fv.IsNil()) {
+			if z, ok := fv.Interface().(zeroer); ok && z.IsZero() {
+				continue
+			}
+		}
+		if hasOption(opts, "omitempty") && isEmptyValue(fv) {
+			continue
+		}
+
+		value, err := json.Marshal(fv.Interface())
+		if err != nil {
+			return nil, err
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(name)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func hasOption(opts, option string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmptyValue reports whether v is empty in the sense of the omitempty JSON
+// option.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return v.IsZero()
+}
@@ -0,0 +1,108 @@
+package chargify
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOptional_marshal(t *testing.T) {
+	v := struct {
+		Unset Optional[bool]   `json:"unset,omitzero"`
+		False Optional[bool]   `json:"false,omitzero"`
+		Zero  Optional[int]    `json:"zero,omitzero"`
+		Null  Optional[string] `json:"null,omitzero"`
+		Value Optional[string] `json:"value,omitzero"`
+	}{
+		False: Set(false),
+		Zero:  Set(0),
+		Null:  Null[string](),
+		Value: Set("x"),
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	if want := `{"false":false,"zero":0,"null":null,"value":"x"}`; string(b) != want {
+		t.Errorf("Marshal returned %s, want %s", b, want)
+	}
+}
+
+func TestOptional_unmarshal(t *testing.T) {
+	var v struct {
+		Unset Optional[int] `json:"unset"`
+		Zero  Optional[int] `json:"zero"`
+		Null  Optional[int] `json:"null"`
+	}
+	if err := json.Unmarshal([]byte(`{"zero":0,"null":null}`), &v); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+
+	if !v.Unset.IsZero() {
+		t.Errorf("Missing field is %+v, want unset", v.Unset)
+	}
+	if got, ok := v.Zero.Get(); !ok || got != 0 {
+		t.Errorf("Zero field Get returned %v, %v, want 0, true", got, ok)
+	}
+	if _, ok := v.Null.Get(); ok || !v.Null.IsNull() || v.Null.IsZero() {
+		t.Errorf("Null field is %+v, want set to null", v.Null)
+	}
+
+	if err := json.Unmarshal([]byte(`{"zero":"x"}`), &v); err == nil {
+		t.Errorf("Unmarshal of a string into Optional[int] returned no error")
+	}
+}
+
+func TestUpdate_marshal(t *testing.T) {
+	tests := []struct {
+		update interface{}
+		want   string
+	}{
+		{&SubscriptionUpdate{}, `{}`},
+		{
+			&SubscriptionUpdate{
+				ProductHandle:         Set("gold"),
+				ReceivesInvoiceEmails: Set(false),
+				NetTerms:              Set(0),
+				Reference:             Null[string](),
+			},
+			`{"product_handle":"gold","receives_invoice_emails":false,"net_terms":0,"reference":null}`,
+		},
+		{
+			SubscriptionUpdate{CreditCardAttributes: &CreditCard{ChargifyToken: "tok_1"}},
+			`{"credit_card_attributes":{"chargify_token":"tok_1"}}`,
+		},
+		{&CustomerUpdate{Email: Set(""), ParentId: Null[int]()}, `{"email":"","parent_id":null}`},
+		{&ProductUpdate{PriceInCents: Set(0), Taxable: Set(false)}, `{"price_in_cents":0,"taxable":false}`},
+	}
+
+	for _, tt := range tests {
+		b, err := json.Marshal(tt.update)
+		if err != nil {
+			t.Fatalf("Marshal(%+v) returned error: %v", tt.update, err)
+		}
+		if string(b) != tt.want {
+			t.Errorf("Marshal(%+v) returned %s, want %s", tt.update, b, tt.want)
+		}
+	}
+}
+
+func TestMarshalUpdate(t *testing.T) {
+	// marshalUpdate leaves out unset Optionals itself, rather than relying on
+	// encoding/json to support omitzero.
+	v := struct {
+		Unset Optional[int]    `json:"unset,omitzero"`
+		Zero  Optional[int]    `json:"zero,omitzero"`
+		Name  string           `json:"name,omitempty"`
+		Kept  string           `json:"kept"`
+		Skip  Optional[string] `json:"-"`
+	}{Zero: Set(0)}
+
+	b, err := marshalUpdate(v)
+	if err != nil {
+		t.Fatalf("marshalUpdate returned error: %v", err)
+	}
+	if want := `{"zero":0,"kept":""}`; string(b) != want {
+		t.Errorf("marshalUpdate returned %s, want %s", b, want)
+	}
+}
@@ -0,0 +1,97 @@
+package chargify
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// An Option configures a Client created by New.
+type Option func(*Client) error
+
+// WithBaseURL sets the URL API requests are sent to, instead of the one
+// derived from the site's subdomain. It is mostly useful for testing against
+// a local server.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) error {
+		if !strings.HasSuffix(baseURL, "/") {
+			baseURL += "/"
+		}
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return fmt.Errorf("chargify: invalid base URL %q: %v", baseURL, err)
+		}
+		if !u.IsAbs() {
+			return fmt.Errorf("chargify: base URL %q is not absolute", baseURL)
+		}
+		c.BaseURL = u
+		return nil
+	}
+}
+
+// WithHTTPClient sets the http.Client used to send requests. If httpClient is
+// nil, http.DefaultClient is used.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) error {
+		if httpClient == nil {
+			httpClient = http.DefaultClient
+		}
+		c.client = httpClient
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request. An empty
+// userAgent omits the header.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) error {
+		c.UserAgent = userAgent
+		return nil
+	}
+}
+
+// WithTimeout limits the time each HTTP request may take, including reading
+// the response body. It applies to a copy of the configured http.Client.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) error {
+		if d < 0 {
+			return errors.New("chargify: timeout must not be negative")
+		}
+		c.timeout = d
+		return nil
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried. By default requests
+// are not retried.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) error {
+		if policy.MaxRetries < 0 {
+			return errors.New("chargify: MaxRetries must not be negative")
+		}
+		c.retryPolicy = policy
+		return nil
+	}
+}
+
+// WithLogger sets the logger the client reports its activity to. At debug
+// level, every request is logged with its method, path, status, latency and
+// Chargify request id; see WithDumpBodies to include headers and bodies.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) error {
+		c.logger = logger
+		return nil
+	}
+}
+
+// WithHooks sets callbacks invoked around every HTTP request the client sends.
+func WithHooks(hooks Hooks) Option {
+	return func(c *Client) error {
+		c.hooks = hooks
+		return nil
+	}
+}
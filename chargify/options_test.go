@@ -0,0 +1,213 @@
+package chargify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	c, err := New(subdomain, apiKey)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	if got, want := c.BaseURL.String(), testBaseDefaultURL; got != want {
+		t.Errorf("New BaseURL is %v, want %v", got, want)
+	}
+	if c.client != http.DefaultClient {
+		t.Errorf("New http client is %v, want http.DefaultClient", c.client)
+	}
+}
+
+func TestNew_options(t *testing.T) {
+	httpClient := &http.Client{}
+	c, err := New(subdomain, apiKey,
+		WithBaseURL("http://localhost:3000/api"),
+		WithHTTPClient(httpClient),
+		WithUserAgent("billing-worker/1.0"),
+		WithTimeout(5*time.Second),
+		WithRetryPolicy(DefaultRetryPolicy),
+	)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	if got, want := c.BaseURL.String(), "http://localhost:3000/api/"; got != want {
+		t.Errorf("New BaseURL is %v, want %v", got, want)
+	}
+	if got, want := c.UserAgent, "billing-worker/1.0"; got != want {
+		t.Errorf("New UserAgent is %v, want %v", got, want)
+	}
+	if got, want := c.client.Timeout, 5*time.Second; got != want {
+		t.Errorf("New http client timeout is %v, want %v", got, want)
+	}
+	if httpClient.Timeout != 0 {
+		t.Errorf("WithTimeout modified the provided http client")
+	}
+	if c.retryPolicy != DefaultRetryPolicy {
+		t.Errorf("New retry policy is %+v, want %+v", c.retryPolicy, DefaultRetryPolicy)
+	}
+}
+
+func TestNew_errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		subdomain string
+		opts      []Option
+	}{
+		{"no subdomain", "", nil},
+		{"invalid subdomain", "acme corp", nil},
+		{"relative base URL", "", []Option{WithBaseURL("/api")}},
+		{"negative timeout", subdomain, []Option{WithTimeout(-time.Second)}},
+		{"negative retries", subdomain, []Option{WithRetryPolicy(RetryPolicy{MaxRetries: -1})}},
+	}
+	for _, tt := range tests {
+		if _, err := New(tt.subdomain, apiKey, tt.opts...); err == nil {
+			t.Errorf("New with %s returned no error", tt.name)
+		}
+	}
+}
+
+func TestDo_retries(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) < 3 {
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"A":"a"}`)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	var hookCalls int
+	c, _ := New("", "",
+		WithBaseURL(server.URL),
+		WithRetryPolicy(RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond}),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+		WithHooks(Hooks{AfterResponse: func(context.Context, *http.Request, *http.Response, error, time.Duration) { hookCalls++ }}),
+	)
+
+	req, _ := c.NewRequest("POST", "/", &Subscription{Id: 3})
+	body := new(struct{ A string })
+	ctx := WithIdempotencyKey(context.Background(), "k")
+	if _, err := c.Do(ctx, req, body); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+
+	if len(bodies) != 3 || bodies[2] != `{"id":3}`+"\n" {
+		t.Errorf("Server received bodies %q, want the request body three times", bodies)
+	}
+	if body.A != "a" {
+		t.Errorf("Response body = %+v, want A: a", body)
+	}
+	if hookCalls != 3 {
+		t.Errorf("AfterResponse hook called %d times, want 3", hookCalls)
+	}
+	if !bytes.Contains(logs.Bytes(), []byte("retrying request")) {
+		t.Errorf("Logger received %q, want retry messages", logs.String())
+	}
+}
+
+func TestDo_retriesExhausted(t *testing.T) {
+	setup()
+	defer teardown()
+	client.retryPolicy = RetryPolicy{MaxRetries: 1, MinBackoff: time.Millisecond}
+
+	requests := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Error(w, "Bad Gateway", http.StatusBadGateway)
+	})
+
+	req, _ := client.NewRequest("GET", "/", nil)
+	resp, err := client.Do(context.Background(), req, nil)
+	if _, ok := err.(*ErrorResponse); !ok {
+		t.Errorf("Do returned error %v, want *ErrorResponse", err)
+	}
+	if resp == nil || resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("Do returned response %v, want status 502", resp)
+	}
+	if requests != 2 {
+		t.Errorf("Server received %d requests, want 2", requests)
+	}
+}
+
+func TestDo_postNotRetried(t *testing.T) {
+	setup()
+	defer teardown()
+	client.retryPolicy = RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond}
+
+	requests := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get(idempotencyKeyHeader) == "" {
+			t.Errorf("Request has no %s header", idempotencyKeyHeader)
+		}
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+	})
+
+	req, _ := client.NewRequest("POST", "/", &Subscription{Id: 3})
+	if _, err := client.Do(context.Background(), req, nil); err == nil {
+		t.Errorf("Do returned no error, want *ErrorResponse")
+	}
+	if requests != 1 {
+		t.Errorf("Server received %d requests, want 1", requests)
+	}
+}
+
+func TestRetryPolicy_shouldRetry(t *testing.T) {
+	p := RetryPolicy{MaxRetries: 2}
+	get, _ := http.NewRequest("GET", "/", nil)
+	post, _ := http.NewRequest("POST", "/", nil)
+	generated, _ := http.NewRequest("POST", "/", nil)
+	generated.Header.Set(idempotencyKeyHeader, "k")
+	keyed, _ := http.NewRequestWithContext(WithIdempotencyKey(context.Background(), "k"), "POST", "/", nil)
+	keyed.Header.Set(idempotencyKeyHeader, "k")
+	noRetry, _ := http.NewRequestWithContext(context.WithValue(context.Background(), noRetryContextKey{}, true), "GET", "/", nil)
+
+	tests := []struct {
+		attempt int
+		req     *http.Request
+		status  int
+		want    bool
+	}{
+		{0, get, http.StatusServiceUnavailable, true},
+		{0, get, http.StatusTooManyRequests, true},
+		{0, get, http.StatusNotImplemented, false},
+		{0, get, http.StatusUnprocessableEntity, false},
+		{2, get, http.StatusServiceUnavailable, false},
+		{0, post, http.StatusServiceUnavailable, false},
+		{0, generated, http.StatusServiceUnavailable, false},
+		{0, keyed, http.StatusServiceUnavailable, true},
+		{0, noRetry, http.StatusServiceUnavailable, false},
+	}
+	for _, tt := range tests {
+		resp := &http.Response{StatusCode: tt.status}
+		if got := p.shouldRetry(tt.attempt, tt.req, resp, nil); got != tt.want {
+			t.Errorf("shouldRetry(%d, %s, %d) = %v, want %v", tt.attempt, tt.req.Method, tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	p := RetryPolicy{MinBackoff: time.Second, MaxBackoff: 10 * time.Second}
+	for attempt, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second} {
+		if got := p.backoff(attempt, nil); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": {"5"}}}
+	if got, want := p.backoff(0, resp), 5*time.Second; got != want {
+		t.Errorf("backoff with Retry-After = %v, want %v", got, want)
+	}
+}
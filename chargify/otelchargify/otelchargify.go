@@ -0,0 +1,162 @@
+// Package otelchargify instruments a chargify.Client with OpenTelemetry.
+//
+// Install the middleware when creating the client:
+//
+//	client, err := chargify.New(subdomain, apiKey,
+//		chargify.WithMiddleware(otelchargify.Middleware()))
+//
+// Every API operation, e.g. Subscriptions.Create, then gets a client span
+// and is recorded in the request duration and error metrics. Retries of an
+// operation are reported on its span rather than as spans of their own.
+package otelchargify
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/m0dd3r/go-chargify/chargify"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the tracer and meter.
+const ScopeName = "github.com/m0dd3r/go-chargify/chargify/otelchargify"
+
+// Attribute keys set on spans and metrics. Metrics only carry the operation,
+// method and status class, which keeps their cardinality bounded; the ids and
+// exact status of a request are on its span.
+const (
+	OperationKey      = attribute.Key("chargify.operation")
+	SubscriptionIDKey = attribute.Key("chargify.subscription_id")
+	RetryCountKey     = attribute.Key("chargify.retry_count")
+	MethodKey         = attribute.Key("http.request.method")
+	StatusCodeKey     = attribute.Key("http.response.status_code")
+	StatusClassKey    = attribute.Key("http.response.status_class")
+)
+
+// Metric names.
+const (
+	DurationMetric = "chargify.client.request.duration"
+	ErrorsMetric   = "chargify.client.request.errors"
+)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// An Option configures Middleware.
+type Option func(*config)
+
+// WithTracerProvider sets the provider spans are created with. It defaults to
+// the global provider.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider sets the provider metrics are recorded with. It defaults
+// to the global provider.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+// Middleware returns a chargify.Middleware that traces every operation and
+// records its duration in seconds, and counts the ones that fail.
+func Middleware(opts ...Option) chargify.Middleware {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	tracer := cfg.tracerProvider.Tracer(ScopeName)
+	meter := cfg.meterProvider.Meter(ScopeName)
+	// Instrument creation only fails for invalid names or options, and the
+	// meter hands back a working no-op instrument when it does.
+	duration, _ := meter.Float64Histogram(DurationMetric,
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of Chargify API operations, including retries."))
+	failures, _ := meter.Int64Counter(ErrorsMetric,
+		metric.WithUnit("{error}"),
+		metric.WithDescription("Chargify API operations that failed or returned an error status."))
+
+	return func(next chargify.Handler) chargify.Handler {
+		return func(ctx context.Context, req *http.Request) (*http.Response, error) {
+			name := req.Method + " " + req.URL.Path
+			if op := chargify.OperationFromContext(ctx); op != nil {
+				name = op.Name
+			}
+
+			attrs := []attribute.KeyValue{
+				OperationKey.String(name),
+				MethodKey.String(req.Method),
+			}
+			spanAttrs := append([]attribute.KeyValue(nil), attrs...)
+			if id, ok := subscriptionID(req.URL.Path); ok {
+				spanAttrs = append(spanAttrs, SubscriptionIDKey.Int(id))
+			}
+
+			ctx, span := tracer.Start(ctx, name,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(spanAttrs...))
+			defer span.End()
+
+			start := time.Now()
+			resp, err := next(ctx, req)
+			elapsed := time.Since(start)
+
+			if op := chargify.OperationFromContext(ctx); op != nil {
+				span.SetAttributes(RetryCountKey.Int(op.Retries))
+			}
+			if resp != nil {
+				attrs = append(attrs, StatusClassKey.String(statusClass(resp.StatusCode)))
+				span.SetAttributes(StatusCodeKey.Int(resp.StatusCode))
+			}
+
+			switch {
+			case err != nil:
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			case resp.StatusCode >= 400:
+				span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+			}
+
+			set := metric.WithAttributes(attrs...)
+			duration.Record(ctx, elapsed.Seconds(), set)
+			if err != nil || resp.StatusCode >= 400 {
+				failures.Add(ctx, 1, set)
+			}
+			return resp, err
+		}
+	}
+}
+
+// statusClass returns the class of an HTTP status code, e.g. "4xx" for 404.
+func statusClass(code int) string {
+	return strconv.Itoa(code/100) + "xx"
+}
+
+var subscriptionPath = regexp.MustCompile(`/subscriptions/(\d+)(?:/|$)`)
+
+// subscriptionID returns the id of the subscription a request path refers
+// to, e.g. 42 for /subscriptions/42/notes.
+func subscriptionID(path string) (int, bool) {
+	m := subscriptionPath.FindStringSubmatch(path)
+	if m == nil {
+		return 0, false
+	}
+	id, err := strconv.Atoi(m[1])
+	return id, err == nil
+}
@@ -0,0 +1,160 @@
+package otelchargify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m0dd3r/go-chargify/chargify"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// setup returns a client sending requests to handler, instrumented with an
+// in-memory span exporter and a manual metric reader.
+func setup(t *testing.T, handler http.Handler) (*chargify.Client, *tracetest.InMemoryExporter, *sdkmetric.ManualReader) {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	exporter := tracetest.NewInMemoryExporter()
+	reader := sdkmetric.NewManualReader()
+	client, err := chargify.New("", "",
+		chargify.WithBaseURL(server.URL),
+		chargify.WithRetryPolicy(chargify.RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}),
+		chargify.WithMiddleware(Middleware(
+			WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))),
+			WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		)),
+	)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	return client, exporter, reader
+}
+
+func attr(attrs []attribute.KeyValue, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestMiddleware_spans(t *testing.T) {
+	calls := 0
+	client, exporter, _ := setup(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"subscription": {"id":42}}`)
+	}))
+
+	if _, _, err := client.Subscriptions.Get(context.Background(), 42); err != nil {
+		t.Fatalf("Subscriptions.Get returned error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("Exported %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "Subscriptions.Get" {
+		t.Errorf("Span name is %q, want Subscriptions.Get", span.Name)
+	}
+	if span.SpanKind != trace.SpanKindClient {
+		t.Errorf("Span kind is %v, want client", span.SpanKind)
+	}
+	if span.Status.Code != codes.Unset {
+		t.Errorf("Span status is %v, want unset", span.Status.Code)
+	}
+	for key, want := range map[attribute.Key]int64{
+		SubscriptionIDKey: 42,
+		RetryCountKey:     1,
+		StatusCodeKey:     200,
+	} {
+		if v, ok := attr(span.Attributes, key); !ok || v.AsInt64() != want {
+			t.Errorf("Span attribute %s is %v, want %d", key, v.AsInterface(), want)
+		}
+	}
+}
+
+func TestMiddleware_metrics(t *testing.T) {
+	client, exporter, reader := setup(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/products" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"subscription": {"id":1}}`)
+	}))
+
+	ctx := context.Background()
+	client.Subscriptions.Get(ctx, 1)
+	client.Products.List(ctx)
+
+	if spans := exporter.GetSpans(); len(spans) != 2 || spans[1].Status.Code != codes.Error {
+		t.Errorf("Exported spans %+v, want a second span with error status", spans)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("Collect returned error: %v", err)
+	}
+
+	var durations uint64
+	var failures []string
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch m.Name {
+			case DurationMetric:
+				for _, dp := range m.Data.(metricdata.Histogram[float64]).DataPoints {
+					durations += dp.Count
+					for _, key := range []attribute.Key{SubscriptionIDKey, StatusCodeKey} {
+						if _, ok := dp.Attributes.Value(key); ok {
+							t.Errorf("Duration has attribute %s, want none", key)
+						}
+					}
+					if class, _ := dp.Attributes.Value(StatusClassKey); class.AsString() != "2xx" && class.AsString() != "4xx" {
+						t.Errorf("Duration status class is %q, want 2xx or 4xx", class.AsString())
+					}
+				}
+			case ErrorsMetric:
+				for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+					op, _ := dp.Attributes.Value(OperationKey)
+					failures = append(failures, fmt.Sprintf("%s=%d", op.AsString(), dp.Value))
+				}
+			}
+		}
+	}
+	if durations != 2 {
+		t.Errorf("Recorded %d durations, want 2", durations)
+	}
+	if len(failures) != 1 || failures[0] != "Products.List=1" {
+		t.Errorf("Recorded errors %q, want [Products.List=1]", failures)
+	}
+}
+
+func TestSubscriptionID(t *testing.T) {
+	for path, want := range map[string]int{
+		"/subscriptions/42":          42,
+		"/subscriptions/42/notes/7":  42,
+		"/subscriptions/42.json":     0,
+		"/subscriptions/metafields":  0,
+		"/customers/42":              0,
+		"/subscription_groups/grp_1": 0,
+	} {
+		if got, _ := subscriptionID(path); got != want {
+			t.Errorf("subscriptionID(%q) is %d, want %d", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package chargify
+
+import "context"
+
+type PaymentProfileWrapper struct {
+	PaymentProfile *CreditCard `json:"payment_profile"`
+}
+
+// PaymentProfilesService handles the payment profiles of customers, which
+// subscriptions are charged to.
+type PaymentProfilesService service
+
+// Create adds a payment profile to the customer identified by
+// profile.CustomerId. Set profile.ChargifyToken to a token from Chargify.js
+// to create it without handling the card details.
+//
+// Chargify API docs: https://reference.chargify.com/v1/payment-profiles/create-a-payment-profile
+func (s *PaymentProfilesService) Create(ctx context.Context, profile *CreditCard) (*CreditCard, *Response, error) {
+	ctx = withOperationName(ctx, "PaymentProfiles.Create")
+
+	req, err := s.client.NewRequest("POST", "payment_profiles", PaymentProfileWrapper{profile})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pw := new(PaymentProfileWrapper)
+	resp, err := s.client.Do(ctx, req, pw)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return pw.PaymentProfile, resp, nil
+}
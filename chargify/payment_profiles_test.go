@@ -0,0 +1,37 @@
+package chargify
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestPaymentProfilesService_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/payment_profiles", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), `{"payment_profile":{"customer_id":14399371,"chargify_token":"tok_9g6hw85pnpt6knmskpwp4ttt"}}`+"\n"; got != want {
+			t.Errorf("Request body = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `{"payment_profile": {"id":10088716,"customer_id":14399371,"masked_card_number":"XXXX-XXXX-XXXX-1111"}}`)
+	})
+
+	profile, _, err := client.PaymentProfiles.Create(context.Background(), &CreditCard{
+		CustomerId:    14399371,
+		ChargifyToken: "tok_9g6hw85pnpt6knmskpwp4ttt",
+	})
+	if err != nil {
+		t.Errorf("PaymentProfiles.Create returned error: %v", err)
+	}
+
+	want := &CreditCard{Id: 10088716, CustomerId: 14399371, MaskedCardNumber: "XXXX-XXXX-XXXX-1111"}
+	if !reflect.DeepEqual(profile, want) {
+		t.Errorf("PaymentProfiles.Create returned %+v, want %+v", profile, want)
+	}
+}
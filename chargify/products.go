@@ -1,6 +1,10 @@
 package chargify
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
 
 type ProductWrapper struct {
 	Product *Product `json:"product"`
@@ -14,16 +18,16 @@ type Product struct {
 	AccountingCode          string              `json:"accounting_code,omitempty"`
 	RequestCreditCard       bool                `json:"request_credit_card,omitempty"`
 	ExpirationInterval      int                 `json:"expiration_interval,omitempty"`
-	ExpirationIntervalUnit  string              `json:"expiration_interval_unit,omitempty"`
+	ExpirationIntervalUnit  IntervalUnit        `json:"expiration_interval_unit,omitempty"`
 	CreatedAt               *FormattedTime      `json:"created_at,omitempty"`
 	UpdatedAt               *FormattedTime      `json:"updated_at,omitempty"`
 	PriceInCents            int                 `json:"price_in_cents,omitempty"`
 	Interval                int                 `json:"interval,omitempty"`
-	IntervalUnit            string              `json:"interval_unit,omitempty"`
+	IntervalUnit            IntervalUnit        `json:"interval_unit,omitempty"`
 	InitialChargeInCents    int                 `json:"initial_charge_in_cents,omitempty"`
 	TrialPriceInCents       int                 `json:"trial_price_in_cents,omitempty"`
 	TrialInterval           int                 `json:"trial_interval,omitempty"`
-	TrialIntervalUnit       string              `json:"trial_interval_unit,omitempty"`
+	TrialIntervalUnit       IntervalUnit        `json:"trial_interval_unit,omitempty"`
 	ArchivedAt              *FormattedTime      `json:"archived_at,omitempty"`
 	RequireCreditCard       bool                `json:"require_credit_card,omitempty"`
 	ReturnParams            string              `json:"return_params,omitempty"`
@@ -34,6 +38,9 @@ type Product struct {
 	UpdateReturnParams      string              `json:"update_return_params,omitempty"`
 	ProductFamily           *ProductFamily      `json:"product_family,omitempty"`
 	PublicSignupPages       []*PublicSignupPage `json:"public_signup_pages,omitempty"`
+
+	// Extra holds the fields of the API response with no field above.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type ProductFamily struct {
@@ -42,6 +49,9 @@ type ProductFamily struct {
 	Handle         string `json:"handle,omitempty"`
 	Description    string `json:"description,omitempty"`
 	AccountingCode string `json:"accounting_code,omitempty"`
+
+	// Extra holds the fields of the API response with no field above.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type PublicSignupPage struct {
@@ -57,6 +67,8 @@ type ProductsService service
 //
 // Chargify API docs: https://reference.chargify.com/v1/products/list-products
 func (s *ProductsService) List(ctx context.Context) ([]*Product, *Response, error) {
+	ctx = withOperationName(ctx, "Products.List")
+
 	u := "products"
 	req, err := s.client.NewRequest("GET", u, nil)
 	if err != nil {
@@ -74,3 +86,55 @@ func (s *ProductsService) List(ctx context.Context) ([]*Product, *Response, erro
 	}
 	return products, resp, nil
 }
+
+// ProductUpdate holds the changes made to a product by ProductsService.Update.
+// Unset fields are left unchanged.
+type ProductUpdate struct {
+	Name                   Optional[string]       `json:"name,omitzero"`
+	Handle                 Optional[string]       `json:"handle,omitzero"`
+	Description            Optional[string]       `json:"description,omitzero"`
+	AccountingCode         Optional[string]       `json:"accounting_code,omitzero"`
+	PriceInCents           Optional[int]          `json:"price_in_cents,omitzero"`
+	Interval               Optional[int]          `json:"interval,omitzero"`
+	IntervalUnit           Optional[IntervalUnit] `json:"interval_unit,omitzero"`
+	InitialChargeInCents   Optional[int]          `json:"initial_charge_in_cents,omitzero"`
+	TrialPriceInCents      Optional[int]          `json:"trial_price_in_cents,omitzero"`
+	TrialInterval          Optional[int]          `json:"trial_interval,omitzero"`
+	TrialIntervalUnit      Optional[IntervalUnit] `json:"trial_interval_unit,omitzero"`
+	ExpirationInterval     Optional[int]          `json:"expiration_interval,omitzero"`
+	ExpirationIntervalUnit Optional[IntervalUnit] `json:"expiration_interval_unit,omitzero"`
+	RequireCreditCard      Optional[bool]         `json:"require_credit_card,omitzero"`
+	RequestCreditCard      Optional[bool]         `json:"request_credit_card,omitzero"`
+	Taxable                Optional[bool]         `json:"taxable,omitzero"`
+	TaxCode                Optional[string]       `json:"tax_code,omitzero"`
+	ReturnParams           Optional[string]       `json:"return_params,omitzero"`
+	UpdateReturnUrl        Optional[string]       `json:"update_return_url,omitzero"`
+}
+
+func (u ProductUpdate) MarshalJSON() ([]byte, error) {
+	return marshalUpdate(u)
+}
+
+// Update changes a product.
+//
+// Chargify API docs: https://reference.chargify.com/v1/products/update-product
+func (s *ProductsService) Update(ctx context.Context, id int, update *ProductUpdate) (*Product, *Response, error) {
+	ctx = withOperationName(ctx, "Products.Update")
+
+	body := struct {
+		Product *ProductUpdate `json:"product"`
+	}{update}
+
+	req, err := s.client.NewRequest("PUT", fmt.Sprintf("products/%d", id), body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pw := new(ProductWrapper)
+	resp, err := s.client.Do(ctx, req, pw)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return pw.Product, resp, nil
+}
@@ -3,6 +3,7 @@ package chargify
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"reflect"
 	"testing"
@@ -27,3 +28,27 @@ func TestProductsService_List_authenticatedUser(t *testing.T) {
 		t.Errorf("Products.List returned %+v, want %+v", products, want)
 	}
 }
+
+func TestProductsService_Update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/products/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), `{"product":{"price_in_cents":0,"expiration_interval_unit":"never"}}`+"\n"; got != want {
+			t.Errorf("Request body = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `{"product": {"id":1,"expiration_interval_unit":"never"}}`)
+	})
+
+	update := &ProductUpdate{PriceInCents: Set(0), ExpirationIntervalUnit: Set(IntervalNever)}
+	product, _, err := client.Products.Update(context.Background(), 1, update)
+	if err != nil {
+		t.Errorf("Products.Update returned error: %v", err)
+	}
+
+	if want := (&Product{Id: 1, ExpirationIntervalUnit: IntervalNever}); !reflect.DeepEqual(product, want) {
+		t.Errorf("Products.Update returned %+v, want %+v", product, want)
+	}
+}
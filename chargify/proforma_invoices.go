@@ -0,0 +1,193 @@
+package chargify
+
+import (
+	"context"
+	"fmt"
+)
+
+// ProformaInvoiceStatus is the state of a ProformaInvoice.
+type ProformaInvoiceStatus string
+
+const (
+	ProformaInvoiceDraft    ProformaInvoiceStatus = "draft"
+	ProformaInvoiceVoided   ProformaInvoiceStatus = "voided"
+	ProformaInvoiceArchived ProformaInvoiceStatus = "archived"
+)
+
+// ProformaInvoice is a copy of what a subscription or subscription group will
+// be invoiced at its next renewal. Amounts are decimal strings in the
+// invoice's currency, e.g. "10.00".
+type ProformaInvoice struct {
+	Uid                 string                     `json:"uid,omitempty"`
+	SiteId              int                        `json:"site_id,omitempty"`
+	CustomerId          int                        `json:"customer_id,omitempty"`
+	SubscriptionId      int                        `json:"subscription_id,omitempty"`
+	Number              int                        `json:"number,omitempty"`
+	SequenceNumber      int                        `json:"sequence_number,omitempty"`
+	CreatedAt           *FormattedTime             `json:"created_at,omitempty"`
+	DeliveryDate        string                     `json:"delivery_date,omitempty"`
+	Status              ProformaInvoiceStatus      `json:"status,omitempty"`
+	CollectionMethod    PaymentCollectionMethod    `json:"collection_method,omitempty"`
+	PaymentInstructions string                     `json:"payment_instructions,omitempty"`
+	Currency            string                     `json:"currency,omitempty"`
+	ConsolidationLevel  string                     `json:"consolidation_level,omitempty"`
+	ProductName         string                     `json:"product_name,omitempty"`
+	ProductFamilyName   string                     `json:"product_family_name,omitempty"`
+	Role                string                     `json:"role,omitempty"`
+	Memo                string                     `json:"memo,omitempty"`
+	SubtotalAmount      string                     `json:"subtotal_amount,omitempty"`
+	DiscountAmount      string                     `json:"discount_amount,omitempty"`
+	TaxAmount           string                     `json:"tax_amount,omitempty"`
+	TotalAmount         string                     `json:"total_amount,omitempty"`
+	CreditAmount        string                     `json:"credit_amount,omitempty"`
+	PaidAmount          string                     `json:"paid_amount,omitempty"`
+	RefundAmount        string                     `json:"refund_amount,omitempty"`
+	DueAmount           string                     `json:"due_amount,omitempty"`
+	LineItems           []*ProformaInvoiceLineItem `json:"line_items,omitempty"`
+	Discounts           []*ProformaInvoiceDiscount `json:"discounts,omitempty"`
+	Taxes               []*ProformaInvoiceTax      `json:"taxes,omitempty"`
+	PublicUrl           string                     `json:"public_url,omitempty"`
+}
+
+type ProformaInvoiceLineItem struct {
+	Uid              string `json:"uid,omitempty"`
+	Title            string `json:"title,omitempty"`
+	Description      string `json:"description,omitempty"`
+	Quantity         string `json:"quantity,omitempty"`
+	UnitPrice        string `json:"unit_price,omitempty"`
+	SubtotalAmount   string `json:"subtotal_amount,omitempty"`
+	DiscountAmount   string `json:"discount_amount,omitempty"`
+	TaxAmount        string `json:"tax_amount,omitempty"`
+	TotalAmount      string `json:"total_amount,omitempty"`
+	TieredUnitPrice  bool   `json:"tiered_unit_price,omitempty"`
+	PeriodRangeStart string `json:"period_range_start,omitempty"`
+	PeriodRangeEnd   string `json:"period_range_end,omitempty"`
+	ProductId        int    `json:"product_id,omitempty"`
+	ProductVersion   int    `json:"product_version,omitempty"`
+	ComponentId      int    `json:"component_id,omitempty"`
+	PricePointId     int    `json:"price_point_id,omitempty"`
+}
+
+type ProformaInvoiceDiscount struct {
+	Uid            string `json:"uid,omitempty"`
+	Title          string `json:"title,omitempty"`
+	Code           string `json:"code,omitempty"`
+	SourceType     string `json:"source_type,omitempty"`
+	DiscountType   string `json:"discount_type,omitempty"`
+	EligibleAmount string `json:"eligible_amount,omitempty"`
+	DiscountAmount string `json:"discount_amount,omitempty"`
+}
+
+type ProformaInvoiceTax struct {
+	Uid           string `json:"uid,omitempty"`
+	Title         string `json:"title,omitempty"`
+	SourceType    string `json:"source_type,omitempty"`
+	Percentage    string `json:"percentage,omitempty"`
+	TaxableAmount string `json:"taxable_amount,omitempty"`
+	TaxAmount     string `json:"tax_amount,omitempty"`
+}
+
+type ProformaInvoicesService service
+
+// Create generates a proforma invoice for a subscription's next renewal.
+//
+// Chargify API docs: https://reference.chargify.com/v1/proforma-invoices/create-proforma-invoice
+func (s *ProformaInvoicesService) Create(ctx context.Context, subscriptionID int) (*ProformaInvoice, *Response, error) {
+	ctx = withOperationName(ctx, "ProformaInvoices.Create")
+	return s.post(ctx, fmt.Sprintf("subscriptions/%d/proforma_invoices", subscriptionID), nil)
+}
+
+// CreateForGroup generates a consolidated proforma invoice for the next
+// renewal of a subscription group.
+//
+// Chargify API docs: https://reference.chargify.com/v1/proforma-invoices/create-consolidated-proforma-invoice
+func (s *ProformaInvoicesService) CreateForGroup(ctx context.Context, groupUID string) (*ProformaInvoice, *Response, error) {
+	ctx = withOperationName(ctx, "ProformaInvoices.CreateForGroup")
+	return s.post(ctx, fmt.Sprintf("subscription_groups/%s/proforma_invoices", groupUID), nil)
+}
+
+// Preview returns what Create would generate for a subscription, without
+// saving it.
+//
+// Chargify API docs: https://reference.chargify.com/v1/proforma-invoices/preview-proforma-invoice
+func (s *ProformaInvoicesService) Preview(ctx context.Context, subscriptionID int) (*ProformaInvoice, *Response, error) {
+	ctx = withOperationName(ctx, "ProformaInvoices.Preview")
+	return s.post(ctx, fmt.Sprintf("subscriptions/%d/proforma_invoices/preview", subscriptionID), nil)
+}
+
+// List fetches the proforma invoices of a subscription.
+//
+// Chargify API docs: https://reference.chargify.com/v1/proforma-invoices/list-subscription-proforma-invoices
+func (s *ProformaInvoicesService) List(ctx context.Context, subscriptionID int, opt *ListOptions) ([]*ProformaInvoice, *Response, error) {
+	ctx = withOperationName(ctx, "ProformaInvoices.List")
+
+	u, err := addOptions(fmt.Sprintf("subscriptions/%d/proforma_invoices", subscriptionID), opt)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	list := new(struct {
+		ProformaInvoices []*ProformaInvoice `json:"proforma_invoices"`
+	})
+	resp, err := s.client.Do(ctx, req, list)
+	if err != nil {
+		return nil, resp, err
+	}
+	resp.setPageValues(opt, len(list.ProformaInvoices))
+
+	return list.ProformaInvoices, resp, nil
+}
+
+// Get fetches a proforma invoice.
+//
+// Chargify API docs: https://reference.chargify.com/v1/proforma-invoices/read-proforma-invoice
+func (s *ProformaInvoicesService) Get(ctx context.Context, uid string) (*ProformaInvoice, *Response, error) {
+	ctx = withOperationName(ctx, "ProformaInvoices.Get")
+
+	req, err := s.client.NewRequest("GET", fmt.Sprintf("proforma_invoices/%s", uid), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	invoice := new(ProformaInvoice)
+	resp, err := s.client.Do(ctx, req, invoice)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return invoice, resp, nil
+}
+
+// Void voids a draft proforma invoice.
+//
+// Chargify API docs: https://reference.chargify.com/v1/proforma-invoices/void-proforma-invoice
+func (s *ProformaInvoicesService) Void(ctx context.Context, uid, reason string) (*ProformaInvoice, *Response, error) {
+	ctx = withOperationName(ctx, "ProformaInvoices.Void")
+
+	body := struct {
+		Void struct {
+			Reason string `json:"reason"`
+		} `json:"void"`
+	}{}
+	body.Void.Reason = reason
+	return s.post(ctx, fmt.Sprintf("proforma_invoices/%s/void", uid), body)
+}
+
+func (s *ProformaInvoicesService) post(ctx context.Context, u string, body interface{}) (*ProformaInvoice, *Response, error) {
+	req, err := s.client.NewRequest("POST", u, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	invoice := new(ProformaInvoice)
+	resp, err := s.client.Do(ctx, req, invoice)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return invoice, resp, nil
+}
@@ -0,0 +1,152 @@
+package chargify
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const proformaInvoiceJSON = `{
+	"uid": "pf_123",
+	"subscription_id": 14900541,
+	"status": "draft",
+	"currency": "USD",
+	"subtotal_amount": "40.0",
+	"discount_amount": "4.0",
+	"tax_amount": "3.3",
+	"total_amount": "39.3",
+	"line_items": [{"uid":"li_1","title":"Basic Plan","quantity":"1.0","unit_price":"40.0","total_amount":"39.3","product_id":3792003}],
+	"discounts": [{"uid":"dli_1","title":"10% off","code":"TENOFF","discount_amount":"4.0"}],
+	"taxes": [{"uid":"tli_1","title":"Sales tax","percentage":"9.17","tax_amount":"3.3"}]
+}`
+
+func testProformaInvoice() *ProformaInvoice {
+	return &ProformaInvoice{
+		Uid:            "pf_123",
+		SubscriptionId: 14900541,
+		Status:         ProformaInvoiceDraft,
+		Currency:       "USD",
+		SubtotalAmount: "40.0",
+		DiscountAmount: "4.0",
+		TaxAmount:      "3.3",
+		TotalAmount:    "39.3",
+		LineItems:      []*ProformaInvoiceLineItem{{Uid: "li_1", Title: "Basic Plan", Quantity: "1.0", UnitPrice: "40.0", TotalAmount: "39.3", ProductId: 3792003}},
+		Discounts:      []*ProformaInvoiceDiscount{{Uid: "dli_1", Title: "10% off", Code: "TENOFF", DiscountAmount: "4.0"}},
+		Taxes:          []*ProformaInvoiceTax{{Uid: "tli_1", Title: "Sales tax", Percentage: "9.17", TaxAmount: "3.3"}},
+	}
+}
+
+func TestProformaInvoicesService_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/14900541/proforma_invoices", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		fmt.Fprint(w, proformaInvoiceJSON)
+	})
+
+	invoice, _, err := client.ProformaInvoices.Create(context.Background(), 14900541)
+	if err != nil {
+		t.Errorf("ProformaInvoices.Create returned error: %v", err)
+	}
+	if want := testProformaInvoice(); !reflect.DeepEqual(invoice, want) {
+		t.Errorf("ProformaInvoices.Create returned %+v, want %+v", invoice, want)
+	}
+}
+
+func TestProformaInvoicesService_CreateForGroup(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscription_groups/grp_123/proforma_invoices", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		fmt.Fprint(w, proformaInvoiceJSON)
+	})
+
+	invoice, _, err := client.ProformaInvoices.CreateForGroup(context.Background(), "grp_123")
+	if err != nil {
+		t.Errorf("ProformaInvoices.CreateForGroup returned error: %v", err)
+	}
+	if want := testProformaInvoice(); !reflect.DeepEqual(invoice, want) {
+		t.Errorf("ProformaInvoices.CreateForGroup returned %+v, want %+v", invoice, want)
+	}
+}
+
+func TestProformaInvoicesService_Preview(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/14900541/proforma_invoices/preview", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		fmt.Fprint(w, proformaInvoiceJSON)
+	})
+
+	invoice, _, err := client.ProformaInvoices.Preview(context.Background(), 14900541)
+	if err != nil {
+		t.Errorf("ProformaInvoices.Preview returned error: %v", err)
+	}
+	if want := testProformaInvoice(); !reflect.DeepEqual(invoice, want) {
+		t.Errorf("ProformaInvoices.Preview returned %+v, want %+v", invoice, want)
+	}
+}
+
+func TestProformaInvoicesService_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/14900541/proforma_invoices", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprintf(w, `{"proforma_invoices": [%s]}`, proformaInvoiceJSON)
+	})
+
+	invoices, _, err := client.ProformaInvoices.List(context.Background(), 14900541, nil)
+	if err != nil {
+		t.Errorf("ProformaInvoices.List returned error: %v", err)
+	}
+	if want := []*ProformaInvoice{testProformaInvoice()}; !reflect.DeepEqual(invoices, want) {
+		t.Errorf("ProformaInvoices.List returned %+v, want %+v", invoices, want)
+	}
+}
+
+func TestProformaInvoicesService_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/proforma_invoices/pf_123", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, proformaInvoiceJSON)
+	})
+
+	invoice, _, err := client.ProformaInvoices.Get(context.Background(), "pf_123")
+	if err != nil {
+		t.Errorf("ProformaInvoices.Get returned error: %v", err)
+	}
+	if want := testProformaInvoice(); !reflect.DeepEqual(invoice, want) {
+		t.Errorf("ProformaInvoices.Get returned %+v, want %+v", invoice, want)
+	}
+}
+
+func TestProformaInvoicesService_Void(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/proforma_invoices/pf_123/void", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), `{"void":{"reason":"Duplicate"}}`+"\n"; got != want {
+			t.Errorf("Request body = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `{"uid":"pf_123","status":"voided"}`)
+	})
+
+	invoice, _, err := client.ProformaInvoices.Void(context.Background(), "pf_123", "Duplicate")
+	if err != nil {
+		t.Errorf("ProformaInvoices.Void returned error: %v", err)
+	}
+	if want := (&ProformaInvoice{Uid: "pf_123", Status: ProformaInvoiceVoided}); !reflect.DeepEqual(invoice, want) {
+		t.Errorf("ProformaInvoices.Void returned %+v, want %+v", invoice, want)
+	}
+}
@@ -0,0 +1,180 @@
+package chargify
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WithRateLimit limits the client to rate requests per second on average,
+// allowing bursts of up to burst requests. Every attempt of a request counts,
+// including retries. Requests wait for their turn, or fail with ctx.Err() if
+// their context ends first.
+//
+// The limit adapts to Chargify's: when it answers 429 Too Many Requests, the
+// rate is halved and requests pause for as long as its Retry-After header
+// asks, then the rate recovers gradually as requests succeed.
+func WithRateLimit(rate float64, burst int) Option {
+	return func(c *Client) error {
+		if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+			return errors.New("chargify: rate limit must be positive")
+		}
+		if burst < 1 {
+			return errors.New("chargify: rate limit burst must be at least 1")
+		}
+		c.limiter = newLimiter(rate, burst)
+		return nil
+	}
+}
+
+// WithMaxInFlight caps how many requests the client sends concurrently. A
+// request is in flight from the moment it is sent until its response body is
+// closed. Requests over the cap wait for their turn, or fail with ctx.Err()
+// if their context ends first.
+func WithMaxInFlight(n int) Option {
+	return func(c *Client) error {
+		if n < 1 {
+			return errors.New("chargify: max in flight must be at least 1")
+		}
+		c.inFlight = make(chan struct{}, n)
+		return nil
+	}
+}
+
+// minRateFraction is the lowest fraction of its configured rate 429
+// responses can slow a limiter down to.
+const minRateFraction = 1.0 / 16
+
+// limiter is a token bucket whose rate backs off multiplicatively when the
+// API reports being over its limit and recovers additively.
+type limiter struct {
+	mu          sync.Mutex
+	maxRate     float64 // configured tokens per second
+	rate        float64 // current tokens per second
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+	now         func() time.Time
+}
+
+func newLimiter(rate float64, burst int) *limiter {
+	return &limiter{
+		maxRate: rate,
+		rate:    rate,
+		burst:   float64(burst),
+		tokens:  float64(burst),
+		now:     time.Now,
+	}
+}
+
+// reserve takes a token and returns how long to wait before using it.
+func (l *limiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.advance(now)
+	l.tokens--
+
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	if pause := l.pausedUntil.Sub(now); pause > wait {
+		wait = pause
+	}
+	return wait
+}
+
+// cancel returns a token taken by reserve that was not used.
+func (l *limiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens++
+}
+
+// advance adds the tokens accumulated since the last update.
+func (l *limiter) advance(now time.Time) {
+	if !l.last.IsZero() && now.After(l.last) {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+}
+
+// wait blocks until the caller may send a request.
+func (l *limiter) wait(ctx context.Context) error {
+	d := l.reserve()
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.cancel()
+		return ctx.Err()
+	}
+}
+
+// observe adapts the rate to the outcome of a request.
+func (l *limiter) observe(resp *http.Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.advance(now)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		l.rate = math.Max(l.rate/2, l.maxRate*minRateFraction)
+		l.tokens = math.Min(l.tokens, 0)
+		if d, ok := retryAfter(resp); ok && now.Add(d).After(l.pausedUntil) {
+			l.pausedUntil = now.Add(d)
+		}
+		return
+	}
+	if resp.StatusCode < 500 {
+		l.rate = math.Min(l.rate+l.maxRate*minRateFraction, l.maxRate)
+	}
+}
+
+// limit waits until the client's rate limit and concurrency cap allow another
+// request to be sent. The returned function must be called once the request
+// is no longer in flight.
+func (c *Client) limit(ctx context.Context) (release func(), err error) {
+	release = func() {}
+	if c.inFlight != nil {
+		select {
+		case c.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		var once sync.Once
+		release = func() { once.Do(func() { <-c.inFlight }) }
+	}
+	if c.limiter != nil {
+		if err := c.limiter.wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+	return release, nil
+}
+
+// releaseBody is a response body that ends its request's turn in flight when
+// closed.
+type releaseBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
@@ -0,0 +1,138 @@
+package chargify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := newLimiter(10, 2)
+	l.now = func() time.Time { return now }
+
+	for i, want := range []time.Duration{0, 0, 100 * time.Millisecond, 200 * time.Millisecond} {
+		if got := l.reserve(); got != want {
+			t.Errorf("reserve %d waits %v, want %v", i, got, want)
+		}
+	}
+
+	now = now.Add(time.Second)
+	l.observe(&http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"3"}}})
+	if l.rate != 5 {
+		t.Errorf("Rate after 429 is %v, want 5", l.rate)
+	}
+	if got, want := l.reserve(), 3*time.Second; got != want {
+		t.Errorf("reserve after 429 waits %v, want %v", got, want)
+	}
+
+	for i := 0; i < 100; i++ {
+		l.observe(&http.Response{StatusCode: http.StatusOK})
+	}
+	if l.rate != 10 {
+		t.Errorf("Rate after successes is %v, want 10", l.rate)
+	}
+
+	for i := 0; i < 100; i++ {
+		l.observe(&http.Response{StatusCode: http.StatusTooManyRequests})
+	}
+	if want := 10 * minRateFraction; l.rate != want {
+		t.Errorf("Rate after many 429s is %v, want %v", l.rate, want)
+	}
+}
+
+func TestDo_rateLimitContext(t *testing.T) {
+	setup()
+	defer teardown()
+	client.limiter = newLimiter(0.1, 1)
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {})
+
+	req, _ := client.NewRequest("GET", "/", nil)
+	if _, err := client.Do(context.Background(), req, nil); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ = client.NewRequest("GET", "/", nil)
+	if _, err := client.Do(ctx, req, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Do returned error %v, want context.DeadlineExceeded", err)
+	}
+	if client.limiter.tokens < -0.5 {
+		t.Errorf("Limiter has %v tokens after a canceled wait, want the reserved one given back", client.limiter.tokens)
+	}
+}
+
+func TestDo_maxInFlight(t *testing.T) {
+	setup()
+	defer teardown()
+	client.inFlight = make(chan struct{}, 2)
+
+	var current, peak int32
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&current, 1)
+		defer atomic.AddInt32(&current, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := client.NewRequest("GET", "/", nil)
+			if _, err := client.Do(context.Background(), req, nil); err != nil {
+				t.Errorf("Do returned error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak != 2 {
+		t.Errorf("Server saw %d requests in flight at most, want 2", peak)
+	}
+	if len(client.inFlight) != 0 {
+		t.Errorf("%d requests still in flight after all returned, want 0", len(client.inFlight))
+	}
+}
+
+func TestCheckResponse_rateLimit(t *testing.T) {
+	res := &http.Response{
+		Request:    &http.Request{},
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": {"3"}},
+		Body:       ioutil.NopCloser(bytes.NewBufferString(`{"errors":["API rate limit exceeded"]}`)),
+	}
+	err, ok := CheckResponse(res).(*RateLimitError)
+	if !ok {
+		t.Fatalf("CheckResponse returned %T, want *RateLimitError", err)
+	}
+	if err.RetryAfter != 3*time.Second || len(err.Errors) != 1 {
+		t.Errorf("CheckResponse returned %+v, want RetryAfter 3s and the error message", err)
+	}
+}
+
+func TestRateLimitOptions_invalid(t *testing.T) {
+	for _, opt := range []Option{
+		WithRateLimit(0, 1),
+		WithRateLimit(1, 0),
+		WithMaxInFlight(0),
+	} {
+		if _, err := New("sub", "key", opt); err == nil {
+			t.Errorf("New returned no error for an invalid limit")
+		}
+	}
+}
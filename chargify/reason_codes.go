@@ -0,0 +1,146 @@
+package chargify
+
+import (
+	"context"
+	"fmt"
+)
+
+type ReasonCodeWrapper struct {
+	ReasonCode *ReasonCode `json:"reason_code"`
+}
+
+// ReasonCode is a reason for canceling a subscription, set up by the site to
+// track churn. Pass its Code in CancellationOptions when canceling.
+type ReasonCode struct {
+	Id          int            `json:"id,omitempty"`
+	SiteId      int            `json:"site_id,omitempty"`
+	Code        string         `json:"code,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Position    int            `json:"position,omitempty"`
+	CreatedAt   *FormattedTime `json:"created_at,omitempty"`
+	UpdatedAt   *FormattedTime `json:"updated_at,omitempty"`
+}
+
+// ReasonCodesService handles the site's cancellation reason codes.
+type ReasonCodesService service
+
+// Create adds a reason code.
+//
+// Chargify API docs: https://reference.chargify.com/v1/reason-codes/create-reason-code
+func (s *ReasonCodesService) Create(ctx context.Context, code *ReasonCode) (*ReasonCode, *Response, error) {
+	ctx = withOperationName(ctx, "ReasonCodes.Create")
+
+	req, err := s.client.NewRequest("POST", "reason_codes", ReasonCodeWrapper{code})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rw := new(ReasonCodeWrapper)
+	resp, err := s.client.Do(ctx, req, rw)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return rw.ReasonCode, resp, nil
+}
+
+// List fetches the reason codes of the site.
+//
+// Chargify API docs: https://reference.chargify.com/v1/reason-codes/list-reason-codes
+func (s *ReasonCodesService) List(ctx context.Context, opt *ListOptions) ([]*ReasonCode, *Response, error) {
+	ctx = withOperationName(ctx, "ReasonCodes.List")
+
+	u, err := addOptions("reason_codes", opt)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var wrappers []*ReasonCodeWrapper
+	resp, err := s.client.Do(ctx, req, &wrappers)
+	if err != nil {
+		return nil, resp, err
+	}
+	var codes []*ReasonCode
+	for _, rw := range wrappers {
+		codes = append(codes, rw.ReasonCode)
+	}
+	resp.setPageValues(opt, len(codes))
+	return codes, resp, nil
+}
+
+// Get fetches a reason code.
+//
+// Chargify API docs: https://reference.chargify.com/v1/reason-codes/read-reason-code
+func (s *ReasonCodesService) Get(ctx context.Context, id int) (*ReasonCode, *Response, error) {
+	ctx = withOperationName(ctx, "ReasonCodes.Get")
+
+	u := fmt.Sprintf("reason_codes/%d", id)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rw := new(ReasonCodeWrapper)
+	resp, err := s.client.Do(ctx, req, rw)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return rw.ReasonCode, resp, nil
+}
+
+// ReasonCodeUpdate holds the changes made to a reason code by
+// ReasonCodesService.Update. Unset fields are left unchanged.
+type ReasonCodeUpdate struct {
+	Code        Optional[string] `json:"code,omitzero"`
+	Description Optional[string] `json:"description,omitzero"`
+	Position    Optional[int]    `json:"position,omitzero"`
+}
+
+func (u ReasonCodeUpdate) MarshalJSON() ([]byte, error) {
+	return marshalUpdate(u)
+}
+
+// Update changes the code, description or position of a reason code.
+//
+// Chargify API docs: https://reference.chargify.com/v1/reason-codes/update-reason-code
+func (s *ReasonCodesService) Update(ctx context.Context, id int, update *ReasonCodeUpdate) (*ReasonCode, *Response, error) {
+	ctx = withOperationName(ctx, "ReasonCodes.Update")
+
+	body := struct {
+		ReasonCode *ReasonCodeUpdate `json:"reason_code"`
+	}{update}
+
+	u := fmt.Sprintf("reason_codes/%d", id)
+	req, err := s.client.NewRequest("PUT", u, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rw := new(ReasonCodeWrapper)
+	resp, err := s.client.Do(ctx, req, rw)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return rw.ReasonCode, resp, nil
+}
+
+// Delete removes a reason code. Subscriptions canceled with it keep it.
+//
+// Chargify API docs: https://reference.chargify.com/v1/reason-codes/delete-reason-code
+func (s *ReasonCodesService) Delete(ctx context.Context, id int) (*Response, error) {
+	ctx = withOperationName(ctx, "ReasonCodes.Delete")
+
+	u := fmt.Sprintf("reason_codes/%d", id)
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
@@ -0,0 +1,121 @@
+package chargify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestReasonCodesService_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	input := &ReasonCode{Code: "PRICE", Description: "Too expensive", Position: 1}
+
+	mux.HandleFunc("/reason_codes", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		v := new(ReasonCodeWrapper)
+		json.NewDecoder(r.Body).Decode(v)
+		if !reflect.DeepEqual(v.ReasonCode, input) {
+			t.Errorf("Request body = %+v, want %+v", v.ReasonCode, input)
+		}
+		fmt.Fprint(w, `{"reason_code": {"id":1,"site_id":2,"code":"PRICE","description":"Too expensive","position":1}}`)
+	})
+
+	code, _, err := client.ReasonCodes.Create(context.Background(), input)
+	if err != nil {
+		t.Errorf("ReasonCodes.Create returned error: %v", err)
+	}
+
+	want := &ReasonCode{Id: 1, SiteId: 2, Code: "PRICE", Description: "Too expensive", Position: 1}
+	if !reflect.DeepEqual(code, want) {
+		t.Errorf("ReasonCodes.Create returned %+v, want %+v", code, want)
+	}
+}
+
+func TestReasonCodesService_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/reason_codes", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got := r.URL.Query().Get("page"); got != "2" {
+			t.Errorf("ReasonCodes.List page is %q, want 2", got)
+		}
+		fmt.Fprint(w, `[{"reason_code": {"id":1,"code":"PRICE"}},{"reason_code": {"id":2,"code":"SWITCH"}}]`)
+	})
+
+	codes, _, err := client.ReasonCodes.List(context.Background(), &ListOptions{Page: 2})
+	if err != nil {
+		t.Errorf("ReasonCodes.List returned error: %v", err)
+	}
+
+	want := []*ReasonCode{{Id: 1, Code: "PRICE"}, {Id: 2, Code: "SWITCH"}}
+	if !reflect.DeepEqual(codes, want) {
+		t.Errorf("ReasonCodes.List returned %+v, want %+v", codes, want)
+	}
+}
+
+func TestReasonCodesService_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/reason_codes/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"reason_code": {"id":1,"code":"PRICE"}}`)
+	})
+
+	code, _, err := client.ReasonCodes.Get(context.Background(), 1)
+	if err != nil {
+		t.Errorf("ReasonCodes.Get returned error: %v", err)
+	}
+
+	want := &ReasonCode{Id: 1, Code: "PRICE"}
+	if !reflect.DeepEqual(code, want) {
+		t.Errorf("ReasonCodes.Get returned %+v, want %+v", code, want)
+	}
+}
+
+func TestReasonCodesService_Update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	input := &ReasonCodeUpdate{Description: Set("Price too high"), Position: Set(0)}
+
+	mux.HandleFunc("/reason_codes/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), `{"reason_code":{"description":"Price too high","position":0}}`+"\n"; got != want {
+			t.Errorf("Request body = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `{"reason_code": {"id":1,"code":"PRICE","description":"Price too high"}}`)
+	})
+
+	code, _, err := client.ReasonCodes.Update(context.Background(), 1, input)
+	if err != nil {
+		t.Errorf("ReasonCodes.Update returned error: %v", err)
+	}
+
+	want := &ReasonCode{Id: 1, Code: "PRICE", Description: "Price too high"}
+	if !reflect.DeepEqual(code, want) {
+		t.Errorf("ReasonCodes.Update returned %+v, want %+v", code, want)
+	}
+}
+
+func TestReasonCodesService_Delete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/reason_codes/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+	})
+
+	_, err := client.ReasonCodes.Delete(context.Background(), 1)
+	if err != nil {
+		t.Errorf("ReasonCodes.Delete returned error: %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package chargify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type ReferralCodeWrapper struct {
+	ReferralCode *ReferralCode `json:"referral_code"`
+}
+
+// ReferralCode is the code a subscription refers new customers with. Pass it
+// in Subscription.Ref when creating the subscriptions of referred customers.
+type ReferralCode struct {
+	Id             int    `json:"id,omitempty"`
+	SiteId         int    `json:"site_id,omitempty"`
+	SubscriptionId int    `json:"subscription_id,omitempty"`
+	Code           string `json:"code,omitempty"`
+}
+
+type ReferralWrapper struct {
+	Referral *Referral `json:"referral"`
+}
+
+// Referral is a subscription created with the referral code of another
+// subscription, the referrer, which is credited for it.
+type Referral struct {
+	Id                     int            `json:"id,omitempty"`
+	ReferralCode           string         `json:"referral_code,omitempty"`
+	ReferrerSubscriptionId int            `json:"referrer_subscription_id,omitempty"`
+	SubscriptionId         int            `json:"subscription_id,omitempty"`
+	CustomerId             int            `json:"customer_id,omitempty"`
+	CreatedAt              *FormattedTime `json:"created_at,omitempty"`
+}
+
+// ReferralCodesService handles the referral codes subscriptions refer new
+// customers with.
+type ReferralCodesService service
+
+// Validate looks up a referral code, such as one entered on a signup form. It
+// returns a nil *ReferralCode and no error for codes that do not exist.
+//
+// Chargify API docs: https://reference.chargify.com/v1/referral-codes/validate-referral-code
+func (s *ReferralCodesService) Validate(ctx context.Context, code string) (*ReferralCode, *Response, error) {
+	ctx = withOperationName(ctx, "ReferralCodes.Validate")
+
+	req, err := s.client.NewRequest("GET", "referral_codes/validate?code="+url.QueryEscape(code), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rw := new(ReferralCodeWrapper)
+	resp, err := s.client.Do(ctx, req, rw)
+	if errResp, ok := err.(*ErrorResponse); ok && errResp.Response.StatusCode == http.StatusNotFound {
+		return nil, resp, nil
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return rw.ReferralCode, resp, nil
+}
+
+// ListReferrals fetches the referrals credited to a subscription.
+//
+// Chargify API docs: https://reference.chargify.com/v1/referral-codes/list-referrals
+func (s *ReferralCodesService) ListReferrals(ctx context.Context, subscriptionID int, opt *ListOptions) ([]*Referral, *Response, error) {
+	ctx = withOperationName(ctx, "ReferralCodes.ListReferrals")
+
+	u, err := addOptions(fmt.Sprintf("subscriptions/%d/referrals", subscriptionID), opt)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var wrappers []*ReferralWrapper
+	resp, err := s.client.Do(ctx, req, &wrappers)
+	if err != nil {
+		return nil, resp, err
+	}
+	var referrals []*Referral
+	for _, r := range wrappers {
+		referrals = append(referrals, r.Referral)
+	}
+	resp.setPageValues(opt, len(referrals))
+	return referrals, resp, nil
+}
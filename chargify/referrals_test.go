@@ -0,0 +1,78 @@
+package chargify
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReferralCodesService_Validate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/referral_codes/validate", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if r.URL.Query().Get("code") != "9b6w4e" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors": ["Invalid referral code."]}`)
+			return
+		}
+		fmt.Fprint(w, `{"referral_code": {"id":1,"site_id":2,"subscription_id":15236915,"code":"9b6w4e"}}`)
+	})
+
+	code, _, err := client.ReferralCodes.Validate(context.Background(), "9b6w4e")
+	if err != nil {
+		t.Errorf("ReferralCodes.Validate returned error: %v", err)
+	}
+	want := &ReferralCode{Id: 1, SiteId: 2, SubscriptionId: 15236915, Code: "9b6w4e"}
+	if !reflect.DeepEqual(code, want) {
+		t.Errorf("ReferralCodes.Validate returned %+v, want %+v", code, want)
+	}
+
+	code, resp, err := client.ReferralCodes.Validate(context.Background(), "bogus")
+	if code != nil || err != nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("ReferralCodes.Validate of an unknown code returned %+v, %v, want nil and no error", code, err)
+	}
+}
+
+func TestReferralCodesService_ListReferrals(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/15236915/referrals", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `[{"referral": {"id":3,"referral_code":"9b6w4e","referrer_subscription_id":15236915,"subscription_id":15236999}}]`)
+	})
+
+	referrals, _, err := client.ReferralCodes.ListReferrals(context.Background(), 15236915, nil)
+	if err != nil {
+		t.Errorf("ReferralCodes.ListReferrals returned error: %v", err)
+	}
+
+	want := []*Referral{{Id: 3, ReferralCode: "9b6w4e", ReferrerSubscriptionId: 15236915, SubscriptionId: 15236999}}
+	if !reflect.DeepEqual(referrals, want) {
+		t.Errorf("ReferralCodes.ListReferrals returned %+v, want %+v", referrals, want)
+	}
+}
+
+func TestSubscriptionsService_Create_ref(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"ref":"9b6w4e"`) {
+			t.Errorf("Request body = %s, want the referral code in ref", body)
+		}
+		fmt.Fprint(w, `{"subscription": {"id":15236999,"referral_code":"x7k2pq"}}`)
+	})
+
+	_, _, err := client.Subscriptions.Create(context.Background(), &Subscription{ProductHandle: "basic", Ref: "9b6w4e"})
+	if err != nil {
+		t.Errorf("Subscriptions.Create returned error: %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package chargify
+
+import (
+	"context"
+	"fmt"
+)
+
+type RenewalPreviewWrapper struct {
+	RenewalPreview *RenewalPreview `json:"renewal_preview"`
+}
+
+// RenewalPreview is what a subscription will be billed at its next renewal,
+// assuming nothing about it changes before then.
+type RenewalPreview struct {
+	NextAssessmentAt       *FormattedTime            `json:"next_assessment_at,omitempty"`
+	SubtotalInCents        int                       `json:"subtotal_in_cents,omitempty"`
+	TotalTaxInCents        int                       `json:"total_tax_in_cents,omitempty"`
+	TotalDiscountInCents   int                       `json:"total_discount_in_cents,omitempty"`
+	TotalInCents           int                       `json:"total_in_cents,omitempty"`
+	ExistingBalanceInCents int                       `json:"existing_balance_in_cents,omitempty"`
+	TotalAmountDueInCents  int                       `json:"total_amount_due_in_cents,omitempty"`
+	UncalculatedTaxes      bool                      `json:"uncalculated_taxes,omitempty"`
+	LineItems              []*RenewalPreviewLineItem `json:"line_items,omitempty"`
+}
+
+type RenewalPreviewLineItem struct {
+	TransactionType       TransactionKind `json:"transaction_type,omitempty"`
+	Kind                  string          `json:"kind,omitempty"`
+	AmountInCents         int             `json:"amount_in_cents,omitempty"`
+	Memo                  string          `json:"memo,omitempty"`
+	DiscountAmountInCents int             `json:"discount_amount_in_cents,omitempty"`
+	TaxableAmountInCents  int             `json:"taxable_amount_in_cents,omitempty"`
+	ProductId             int             `json:"product_id,omitempty"`
+	ProductHandle         string          `json:"product_handle,omitempty"`
+	ProductName           string          `json:"product_name,omitempty"`
+	ComponentId           int             `json:"component_id,omitempty"`
+	ComponentHandle       string          `json:"component_handle,omitempty"`
+	ComponentName         string          `json:"component_name,omitempty"`
+	PeriodRangeStart      string          `json:"period_range_start,omitempty"`
+	PeriodRangeEnd        string          `json:"period_range_end,omitempty"`
+}
+
+// PreviewRenewal fetches a preview of the charges, discounts and taxes of a
+// subscription's next renewal.
+//
+// Chargify API docs: https://reference.chargify.com/v1/subscriptions/preview-renewal
+func (s *SubscriptionsService) PreviewRenewal(ctx context.Context, id int) (*RenewalPreview, *Response, error) {
+	ctx = withOperationName(ctx, "Subscriptions.PreviewRenewal")
+
+	u := fmt.Sprintf("subscriptions/%d/renewals/preview", id)
+	req, err := s.client.NewRequest("POST", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rw := new(RenewalPreviewWrapper)
+	resp, err := s.client.Do(ctx, req, rw)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return rw.RenewalPreview, resp, nil
+}
@@ -0,0 +1,68 @@
+package chargify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSubscriptionsService_PreviewRenewal(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/14900541/renewals/preview", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		fmt.Fprint(w, `{"renewal_preview": {
+			"next_assessment_at": "2016-12-01T11:41:25-05:00",
+			"subtotal_in_cents": 4000,
+			"total_tax_in_cents": 330,
+			"total_discount_in_cents": 400,
+			"total_in_cents": 3930,
+			"existing_balance_in_cents": 2450,
+			"total_amount_due_in_cents": 6380,
+			"uncalculated_taxes": false,
+			"line_items": [{
+				"transaction_type": "charge",
+				"kind": "baseline",
+				"amount_in_cents": 4000,
+				"memo": "Basic Plan (12/01/2016 - 01/01/2017)",
+				"discount_amount_in_cents": 400,
+				"taxable_amount_in_cents": 3600,
+				"product_id": 3792003,
+				"product_handle": "basic",
+				"product_name": "$10 Basic Plan"
+			}]
+		}}`)
+	})
+
+	preview, _, err := client.Subscriptions.PreviewRenewal(context.Background(), 14900541)
+	if err != nil {
+		t.Errorf("Subscriptions.PreviewRenewal returned error: %v", err)
+	}
+
+	want := &RenewalPreview{
+		NextAssessmentAt:       NewFormattedTime(`"2016-12-01T11:41:25-05:00"`),
+		SubtotalInCents:        4000,
+		TotalTaxInCents:        330,
+		TotalDiscountInCents:   400,
+		TotalInCents:           3930,
+		ExistingBalanceInCents: 2450,
+		TotalAmountDueInCents:  6380,
+		LineItems: []*RenewalPreviewLineItem{{
+			TransactionType:       TransactionCharge,
+			Kind:                  "baseline",
+			AmountInCents:         4000,
+			Memo:                  "Basic Plan (12/01/2016 - 01/01/2017)",
+			DiscountAmountInCents: 400,
+			TaxableAmountInCents:  3600,
+			ProductId:             3792003,
+			ProductHandle:         "basic",
+			ProductName:           "$10 Basic Plan",
+		}},
+	}
+	if !reflect.DeepEqual(preview, want) {
+		t.Errorf("Subscriptions.PreviewRenewal returned %+v, want %+v", preview, want)
+	}
+}
@@ -0,0 +1,98 @@
+package chargify
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMinBackoff = 500 * time.Millisecond
+	defaultMaxBackoff = 30 * time.Second
+)
+
+// RetryPolicy controls how Do retries requests that failed without a response
+// or with a 429 or 5xx status. Requests are only retried if repeating them is
+// safe: their method is idempotent or the caller supplied their idempotency
+// key with WithIdempotencyKey. The keys generated for other requests are not
+// known to make resending them safe. The zero value disables retries.
+type RetryPolicy struct {
+	// MaxRetries is how many times a request is retried.
+	MaxRetries int
+
+	// MinBackoff is the wait before the first retry. It doubles with every
+	// further retry. Default: 500ms.
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the wait between retries, including waits requested
+	// by a Retry-After header. Default: 30s.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is a RetryPolicy suitable for most clients.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	MinBackoff: defaultMinBackoff,
+	MaxBackoff: defaultMaxBackoff,
+}
+
+// noRetryContextKey marks the context of requests that are retried by their
+// caller, such as CreateOnce, rather than by the client's retry policy.
+type noRetryContextKey struct{}
+
+// shouldRetry reports whether req should be sent again after its attempt-th
+// retry (0 for the first try) ended with resp or err.
+func (p RetryPolicy) shouldRetry(attempt int, req *http.Request, resp *http.Response, err error) bool {
+	ctx := req.Context()
+	if attempt >= p.MaxRetries || ctx.Err() != nil || ctx.Value(noRetryContextKey{}) != nil {
+		return false
+	}
+	switch req.Method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+	default:
+		if _, ok := IdempotencyKeyFromContext(ctx); !ok {
+			return false
+		}
+	}
+	if err != nil {
+		return true
+	}
+	code := resp.StatusCode
+	return code == http.StatusTooManyRequests || code >= 500 && code != http.StatusNotImplemented
+}
+
+// backoff returns how long to wait before retrying a request whose attempt-th
+// retry ended with resp, which may be nil.
+func (p RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	min, max := p.MinBackoff, p.MaxBackoff
+	if min <= 0 {
+		min = defaultMinBackoff
+	}
+	if max <= 0 {
+		max = defaultMaxBackoff
+	}
+
+	d := min
+	for i := 0; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if resp != nil {
+		if after, ok := retryAfter(resp); ok && after > d {
+			d = after
+		}
+	}
+	if d > max {
+		d = max
+	}
+	return d
+}
+
+// retryAfter returns the wait requested by the Retry-After header of resp, if
+// it has one in seconds.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	secs, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || secs < 0 {
+		return 0, false
+	}
+	return time.Duration(secs) * time.Second, true
+}
@@ -0,0 +1,209 @@
+package chargify
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SchemaMismatch is a field of an API response that does not match the type
+// it is decoded into.
+type SchemaMismatch struct {
+	// Field is the path of the field in the response, such as
+	// "subscription.customer.tier" or "[0].event.id".
+	Field string
+	// Type is the Go type the field is decoded into or, for unknown fields,
+	// the type of the object holding it.
+	Type reflect.Type
+	// Unknown is set for fields with no counterpart in Type, which are
+	// otherwise dropped or kept in an Extra field.
+	Unknown bool
+	// Value is the JSON value of the field.
+	Value json.RawMessage
+	// Err is the error decoding Value into Type, for known fields.
+	Err error
+}
+
+func (m *SchemaMismatch) Error() string {
+	if m.Unknown {
+		return fmt.Sprintf("chargify: unknown field %s in %v", m.Field, m.Type)
+	}
+	return fmt.Sprintf("chargify: cannot decode field %s into %v: %v", m.Field, m.Type, m.Err)
+}
+
+// CheckSchema compares the JSON document data with the type of v, usually a
+// pointer to the value data is decoded into, and returns its unknown fields
+// and the fields whose values do not decode into the type of their field,
+// ordered by path. Unlike json.Decoder.DisallowUnknownFields, it reports every
+// mismatch rather than the first one, and sees through the types that keep
+// unknown fields in Extra.
+//
+// Values whose type has its own UnmarshalJSON method, such as FormattedTime,
+// are only checked to decode.
+func CheckSchema(data []byte, v interface{}) []SchemaMismatch {
+	var mismatches []SchemaMismatch
+	checkValue(&mismatches, "", json.RawMessage(data), reflect.TypeOf(v))
+	sort.SliceStable(mismatches, func(i, j int) bool {
+		return mismatches[i].Field < mismatches[j].Field
+	})
+	return mismatches
+}
+
+var (
+	unmarshalerType     = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	rawMessageType      = reflect.TypeOf(json.RawMessage(nil))
+	extraType           = reflect.TypeOf(map[string]json.RawMessage(nil))
+)
+
+// checkValue appends to mismatches the differences between raw, the value of
+// the field at path, and type t.
+func checkValue(mismatches *[]SchemaMismatch, path string, raw json.RawMessage, t reflect.Type) {
+	if t == nil || bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		return
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() == reflect.Struct && hasExtra(t) {
+		checkObject(mismatches, path, raw, t)
+		return
+	}
+	if reflect.PointerTo(t).Implements(unmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		checkDecode(mismatches, path, raw, t)
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		checkObject(mismatches, path, raw, t)
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			checkDecode(mismatches, path, raw, t)
+			return
+		}
+		for i, item := range items {
+			checkValue(mismatches, path+"["+strconv.Itoa(i)+"]", item, t.Elem())
+		}
+	case reflect.Map:
+		var items map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			checkDecode(mismatches, path, raw, t)
+			return
+		}
+		for key, item := range items {
+			checkValue(mismatches, joinPath(path, key), item, t.Elem())
+		}
+	case reflect.Interface:
+		// Anything decodes into an interface.
+	default:
+		checkDecode(mismatches, path, raw, t)
+	}
+}
+
+// checkObject checks the fields of raw, a JSON object, against struct type t.
+func checkObject(mismatches *[]SchemaMismatch, path string, raw json.RawMessage, t reflect.Type) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		checkDecode(mismatches, path, raw, t)
+		return
+	}
+
+	known := jsonFields(t)
+	for name, value := range fields {
+		field, ok := known[strings.ToLower(name)]
+		switch {
+		case !ok:
+			*mismatches = append(*mismatches, SchemaMismatch{
+				Field:   joinPath(path, name),
+				Type:    t,
+				Unknown: true,
+				Value:   value,
+			})
+		case field.quoted:
+			var s string
+			if err := json.Unmarshal(value, &s); err != nil {
+				checkDecode(mismatches, joinPath(path, name), value, field.typ)
+				continue
+			}
+			checkValue(mismatches, joinPath(path, name), json.RawMessage(s), field.typ)
+		default:
+			checkValue(mismatches, joinPath(path, name), value, field.typ)
+		}
+	}
+}
+
+// checkDecode records a mismatch if raw does not decode into type t.
+func checkDecode(mismatches *[]SchemaMismatch, path string, raw json.RawMessage, t reflect.Type) {
+	if t == rawMessageType {
+		return
+	}
+	if err := json.Unmarshal(raw, reflect.New(t).Interface()); err != nil {
+		*mismatches = append(*mismatches, SchemaMismatch{
+			Field: path,
+			Type:  t,
+			Value: raw,
+			Err:   err,
+		})
+	}
+}
+
+// hasExtra reports whether struct type t keeps its unknown fields in Extra.
+func hasExtra(t reflect.Type) bool {
+	f, ok := t.FieldByName("Extra")
+	return ok && f.Type == extraType && f.Tag.Get("json") == "-"
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// WithSchemaCheck makes the client compare every JSON response with the type
+// it is decoded into, and call report with the request and the mismatches
+// found by CheckSchema for responses that differ. The check never fails a
+// call that succeeds without it, so it can run in production to notice
+// changes to the API before they break anything, as well as in tests against
+// a recording of the API.
+//
+// Checking decodes every response twice; enable it where the cost does not
+// matter.
+func WithSchemaCheck(report func(req *http.Request, mismatches []SchemaMismatch)) Option {
+	return func(c *Client) error {
+		c.schemaCheck = report
+		return nil
+	}
+}
+
+// decodeChecked decodes the JSON document in body into v like Do, and reports
+// the mismatches between them to the client's schema check.
+func (c *Client) decodeChecked(req *http.Request, body io.Reader, v interface{}) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil // ignore empty response bodies, as Do does
+	}
+	err = json.Unmarshal(data, v)
+	var typeErr *json.UnmarshalTypeError
+	if err != nil && !errors.As(err, &typeErr) {
+		return err
+	}
+	if mismatches := CheckSchema(data, v); len(mismatches) > 0 {
+		c.schemaCheck(req, mismatches)
+	}
+	return err
+}
@@ -0,0 +1,88 @@
+package chargify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestCheckSchema(t *testing.T) {
+	data := `{"subscription": {
+		"id": "12",
+		"state": "active",
+		"signup_revenue": "1.50",
+		"created_at": "yesterday",
+		"tier": "gold",
+		"customer": {"id": 3, "Email": "a@example.com", "locale": "en"},
+		"product": {"public_signup_pages": [{"id": 1}, {"id": 2, "slug": "x"}]}
+	}}`
+
+	got := CheckSchema([]byte(data), new(SubscriptionWrapper))
+
+	type mismatch struct {
+		Field   string
+		Type    string
+		Unknown bool
+	}
+	var fields []mismatch
+	for _, m := range got {
+		fields = append(fields, mismatch{m.Field, m.Type.String(), m.Unknown})
+	}
+	want := []mismatch{
+		{"subscription.created_at", "chargify.FormattedTime", false},
+		{"subscription.customer.locale", "chargify.Customer", true},
+		{"subscription.id", "int", false},
+		{"subscription.product.public_signup_pages[1].slug", "chargify.PublicSignupPage", true},
+		{"subscription.tier", "chargify.Subscription", true},
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("CheckSchema returned %+v, want %+v", fields, want)
+	}
+	if got[0].Err == nil || string(got[2].Value) != `"12"` {
+		t.Errorf("CheckSchema returned %+v, want the values and decoding errors of mismatches", got)
+	}
+}
+
+func TestCheckSchema_match(t *testing.T) {
+	data := `[{"event": {"id": 1, "key": "signup_success", "event_specific_data": {"anything": true}}}]`
+	if got := CheckSchema([]byte(data), new([]EventWrapper)); len(got) != 0 {
+		t.Errorf("CheckSchema returned %+v, want no mismatches", got)
+	}
+}
+
+func TestWithSchemaCheck(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var reported []SchemaMismatch
+	WithSchemaCheck(func(req *http.Request, mismatches []SchemaMismatch) {
+		reported = append(reported, mismatches...)
+	})(client)
+
+	mux.HandleFunc("/subscriptions/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"subscription": {"id": 1, "tier": "gold"}}`)
+	})
+	mux.HandleFunc("/subscriptions/2", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"subscription": {"id": "2"}}`)
+	})
+
+	sub, _, err := client.Subscriptions.Get(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Subscriptions.Get returned error: %v", err)
+	}
+	if sub.Id != 1 || len(reported) != 1 || reported[0].Field != "subscription.tier" {
+		t.Errorf("Subscriptions.Get returned %+v and reported %+v, want tier reported", sub, reported)
+	}
+
+	// A type mismatch is reported, and fails the call as it would without
+	// the check.
+	reported = nil
+	if _, _, err := client.Subscriptions.Get(context.Background(), 2); err == nil {
+		t.Errorf("Subscriptions.Get of a mismatched id returned no error")
+	}
+	if len(reported) != 1 || reported[0].Field != "subscription.id" || reported[0].Unknown {
+		t.Errorf("Schema check reported %+v, want the mismatched id", reported)
+	}
+}
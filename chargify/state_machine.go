@@ -0,0 +1,171 @@
+package chargify
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SubscriptionAction is an action that changes a subscription's lifecycle.
+type SubscriptionAction string
+
+const (
+	ActionCancel        SubscriptionAction = "cancel"
+	ActionDelayedCancel SubscriptionAction = "delayed_cancel"
+	ActionMigrate       SubscriptionAction = "migrate"
+	ActionAddCoupon     SubscriptionAction = "add_coupon"
+	ActionRetry         SubscriptionAction = "retry"
+)
+
+// transitions lists the states each action is allowed in.
+var transitions = map[SubscriptionAction][]SubscriptionState{
+	ActionCancel: {
+		StateActive, StateAssessing, StatePending, StateTrialing, StatePastDue,
+		StateSoftFailure, StateUnpaid, StateOnHold, StatePaused, StateAwaitingSignup,
+	},
+	ActionDelayedCancel: {
+		StateActive, StateAssessing, StatePending, StateTrialing, StatePastDue, StateSoftFailure,
+	},
+	ActionMigrate: {
+		StateActive, StateAssessing, StatePending, StateTrialing, StatePastDue, StateSoftFailure,
+	},
+	ActionAddCoupon: {
+		StateActive, StateAssessing, StatePending, StateTrialing, StatePastDue,
+		StateSoftFailure, StateAwaitingSignup,
+	},
+	ActionRetry: {
+		StatePastDue, StateSoftFailure, StateUnpaid,
+	},
+}
+
+// CanTransition reports whether Chargify allows action on a subscription in
+// state from. Unknown actions and states are allowed, since Chargify may
+// support them.
+//
+// Chargify API docs: https://help.chargify.com/subscriptions/subscription-states.html
+func CanTransition(from SubscriptionState, action SubscriptionAction) bool {
+	allowed, ok := transitions[action]
+	if !ok || !from.known() {
+		return true
+	}
+	for _, state := range allowed {
+		if state == from {
+			return true
+		}
+	}
+	return false
+}
+
+// known reports whether s is one of the documented subscription states.
+func (s SubscriptionState) known() bool {
+	switch s {
+	case StateActive, StateAssessing, StatePending, StateTrialing, StatePastDue,
+		StateSoftFailure, StateUnpaid, StateCanceled, StateExpired, StateTrialEnded,
+		StateOnHold, StatePaused, StateSuspended, StateAwaitingSignup, StateFailedToCreate:
+		return true
+	}
+	return false
+}
+
+// InvalidTransitionError is returned by SubscriptionsService methods of a
+// client created with WithTransitionChecks, without calling the API, for
+// actions the last known state of the subscription does not allow.
+type InvalidTransitionError struct {
+	SubscriptionId int
+	State          SubscriptionState
+	Action         SubscriptionAction
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("chargify: cannot %s subscription %d in state %s", e.Action, e.SubscriptionId, e.State)
+}
+
+// WithTransitionChecks makes the client remember the state of the
+// subscriptions it receives from the API for ttl, and fail actions on them
+// that state does not allow with an *InvalidTransitionError instead of
+// sending them. Once ttl has passed, a subscription's state is forgotten and
+// its actions are left to Chargify to check again.
+//
+// States changed by other clients are only seen once the subscription is
+// fetched again, so ttl should be short enough for a stale state not to
+// reject valid actions.
+func WithTransitionChecks(ttl time.Duration) Option {
+	return func(c *Client) error {
+		if ttl <= 0 {
+			return errors.New("chargify: transition check TTL must be positive")
+		}
+		c.states = newStateTracker(ttl)
+		return nil
+	}
+}
+
+// stateTracker remembers the last known state of subscriptions by id for a
+// limited time.
+type stateTracker struct {
+	mu        sync.Mutex
+	states    map[int]trackedState
+	ttl       time.Duration
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+type trackedState struct {
+	state SubscriptionState
+	seen  time.Time
+}
+
+func newStateTracker(ttl time.Duration) *stateTracker {
+	return &stateTracker{
+		states:    make(map[int]trackedState),
+		ttl:       ttl,
+		now:       time.Now,
+		lastSweep: time.Now(),
+	}
+}
+
+// observe records the states of subs. It does nothing on a nil tracker.
+func (t *stateTracker) observe(subs ...*Subscription) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	for _, sub := range subs {
+		if sub != nil && sub.Id != 0 && sub.State != "" {
+			t.states[sub.Id] = trackedState{state: sub.State, seen: now}
+		}
+	}
+
+	// Forget expired states now and then, so that the tracker only holds
+	// the subscriptions seen in the last two TTLs.
+	if now.Sub(t.lastSweep) >= t.ttl {
+		for id, tracked := range t.states {
+			if now.Sub(tracked.seen) >= t.ttl {
+				delete(t.states, id)
+			}
+		}
+		t.lastSweep = now
+	}
+}
+
+// check returns an *InvalidTransitionError if the last known state of a
+// subscription does not allow action. Expired states are unknown. It allows
+// everything on a nil tracker.
+func (t *stateTracker) check(id int, action SubscriptionAction) error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	tracked, ok := t.states[id]
+	if ok && t.now().Sub(tracked.seen) >= t.ttl {
+		delete(t.states, id)
+		ok = false
+	}
+	t.mu.Unlock()
+	if ok && !CanTransition(tracked.state, action) {
+		return &InvalidTransitionError{SubscriptionId: id, State: tracked.state, Action: action}
+	}
+	return nil
+}
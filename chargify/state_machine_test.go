@@ -0,0 +1,119 @@
+package chargify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from   SubscriptionState
+		action SubscriptionAction
+		want   bool
+	}{
+		{StateActive, ActionCancel, true},
+		{StateCanceled, ActionCancel, false},
+		{StateTrialing, ActionDelayedCancel, true},
+		{StateOnHold, ActionDelayedCancel, false},
+		{StatePastDue, ActionMigrate, true},
+		{StateExpired, ActionMigrate, false},
+		{StateSoftFailure, ActionRetry, true},
+		{StateActive, ActionRetry, false},
+		{SubscriptionState("hibernating"), ActionCancel, true},
+		{StateActive, SubscriptionAction("teleport"), true},
+	}
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.action); got != tt.want {
+			t.Errorf("CanTransition(%q, %q) is %v, want %v", tt.from, tt.action, got, tt.want)
+		}
+	}
+}
+
+func TestSubscriptionsService_transitionChecks(t *testing.T) {
+	setup()
+	defer teardown()
+	WithTransitionChecks(time.Minute)(client)
+
+	deletes := 0
+	mux.HandleFunc("/subscriptions/14900541", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			deletes++
+		}
+		fmt.Fprint(w, testSubJSON("canceled"))
+	})
+
+	ctx := context.Background()
+	if _, _, err := client.Subscriptions.Destroy(ctx, 14900541); err != nil {
+		t.Fatalf("Subscriptions.Destroy of an unknown subscription returned error: %v", err)
+	}
+
+	_, _, err := client.Subscriptions.Destroy(ctx, 14900541)
+	var transitionErr *InvalidTransitionError
+	if !errors.As(err, &transitionErr) {
+		t.Fatalf("Subscriptions.Destroy of a canceled subscription returned %v, want *InvalidTransitionError", err)
+	}
+	want := InvalidTransitionError{SubscriptionId: 14900541, State: StateCanceled, Action: ActionCancel}
+	if *transitionErr != want {
+		t.Errorf("Subscriptions.Destroy returned %+v, want %+v", *transitionErr, want)
+	}
+	if deletes != 1 {
+		t.Errorf("Server received %d deletes, want 1", deletes)
+	}
+
+	if _, err := client.Subscriptions.DelayedCancel(ctx, 14900541); !errors.As(err, &transitionErr) {
+		t.Errorf("Subscriptions.DelayedCancel of a canceled subscription returned %v, want *InvalidTransitionError", err)
+	}
+}
+
+func TestSubscriptionsService_noTransitionChecks(t *testing.T) {
+	setup()
+	defer teardown()
+
+	deletes := 0
+	mux.HandleFunc("/subscriptions/14900541", func(w http.ResponseWriter, r *http.Request) {
+		deletes++
+		fmt.Fprint(w, testSubJSON("canceled"))
+	})
+
+	ctx := context.Background()
+	client.Subscriptions.Destroy(ctx, 14900541)
+	if _, _, err := client.Subscriptions.Destroy(ctx, 14900541); err != nil {
+		t.Errorf("Subscriptions.Destroy returned error: %v", err)
+	}
+	if deletes != 2 {
+		t.Errorf("Server received %d deletes, want 2", deletes)
+	}
+}
+
+func TestStateTracker_expiry(t *testing.T) {
+	now := time.Now()
+	tracker := newStateTracker(time.Minute)
+	tracker.now = func() time.Time { return now }
+
+	tracker.observe(&Subscription{Id: 1, State: StateCanceled})
+	if err := tracker.check(1, ActionCancel); err == nil {
+		t.Errorf("check of a canceled subscription returned no error")
+	}
+
+	now = now.Add(time.Minute)
+	if err := tracker.check(1, ActionCancel); err != nil {
+		t.Errorf("check of an expired state returned error: %v", err)
+	}
+
+	tracker.observe(&Subscription{Id: 2, State: StateActive})
+	now = now.Add(time.Minute)
+	tracker.observe(&Subscription{Id: 3, State: StateActive})
+	if len(tracker.states) != 1 {
+		t.Errorf("Tracker holds %d states, want 1 after expired ones are swept", len(tracker.states))
+	}
+}
+
+func TestWithTransitionChecks_invalidTTL(t *testing.T) {
+	if _, err := New(subdomain, apiKey, WithTransitionChecks(0)); err == nil {
+		t.Errorf("New with a zero transition check TTL returned no error")
+	}
+}
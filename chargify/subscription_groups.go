@@ -0,0 +1,248 @@
+package chargify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+type SubscriptionGroupWrapper struct {
+	SubscriptionGroup *SubscriptionGroup `json:"subscription_group"`
+}
+
+// SubscriptionGroup is a set of subscriptions billed together to the payment
+// profile of their primary subscription.
+type SubscriptionGroup struct {
+	Uid                         string                     `json:"uid,omitempty"`
+	Scheme                      int                        `json:"scheme,omitempty"`
+	CustomerId                  int                        `json:"customer_id,omitempty"`
+	Customer                    *Customer                  `json:"customer,omitempty"`
+	PaymentProfileId            int                        `json:"payment_profile_id,omitempty"`
+	PaymentProfile              *CreditCard                `json:"payment_profile,omitempty"`
+	PaymentCollectionMethod     PaymentCollectionMethod    `json:"payment_collection_method,omitempty"`
+	SubscriptionIds             []int                      `json:"subscription_ids,omitempty"`
+	PrimarySubscriptionId       int                        `json:"primary_subscription_id,omitempty"`
+	NextAssessmentAt            *FormattedTime             `json:"next_assessment_at,omitempty"`
+	State                       SubscriptionState          `json:"state,omitempty"`
+	CancelAtEndOfPeriod         bool                       `json:"cancel_at_end_of_period,omitempty"`
+	CurrentBillingAmountInCents int                        `json:"current_billing_amount_in_cents,omitempty"`
+	AccountBalances             *SubscriptionGroupBalances `json:"account_balances,omitempty"`
+	Subscriptions               []*Subscription            `json:"subscriptions,omitempty"`
+	CreatedAt                   *FormattedTime             `json:"created_at,omitempty"`
+
+	// Extra holds the fields of the API response with no field above.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// SubscriptionGroupBalances are the balances held by a subscription group.
+type SubscriptionGroupBalances struct {
+	Prepayments    *AccountBalance `json:"prepayments,omitempty"`
+	ServiceCredits *AccountBalance `json:"service_credits,omitempty"`
+	OpenInvoices   *AccountBalance `json:"open_invoices,omitempty"`
+}
+
+type AccountBalance struct {
+	BalanceInCents int `json:"balance_in_cents,omitempty"`
+}
+
+// SubscriptionGroupSignup describes a customer and the subscriptions to create
+// for them as a group. The first entry of Subscriptions becomes the group's
+// primary subscription.
+type SubscriptionGroupSignup struct {
+	PaymentProfileId        int                     `json:"payment_profile_id,omitempty"`
+	PayerId                 int                     `json:"payer_id,omitempty"`
+	PayerReference          string                  `json:"payer_reference,omitempty"`
+	PaymentCollectionMethod PaymentCollectionMethod `json:"payment_collection_method,omitempty"`
+	PayerAttributes         *Customer               `json:"payer_attributes,omitempty"`
+	CreditCardAttributes    *CreditCard             `json:"credit_card_attributes,omitempty"`
+	Subscriptions           []*Subscription         `json:"-"`
+}
+
+// GroupMembershipBilling controls how charges are handled when a subscription
+// joins a group.
+type GroupMembershipBilling struct {
+	Accrue    bool `json:"accrue,omitempty"`
+	AlignDate bool `json:"align_date,omitempty"`
+	Prorate   bool `json:"prorate,omitempty"`
+}
+
+type SubscriptionGroupsService service
+
+// Create groups the given member subscriptions under a primary subscription.
+//
+// Chargify API docs: https://reference.chargify.com/v1/subscription-groups/create-subscription-group
+func (s *SubscriptionGroupsService) Create(ctx context.Context, primarySubscriptionID int, memberIDs []int) (*SubscriptionGroup, *Response, error) {
+	ctx = withOperationName(ctx, "SubscriptionGroups.Create")
+
+	body := struct {
+		SubscriptionGroup struct {
+			SubscriptionId int   `json:"subscription_id"`
+			MemberIds      []int `json:"member_ids,omitempty"`
+		} `json:"subscription_group"`
+	}{}
+	body.SubscriptionGroup.SubscriptionId = primarySubscriptionID
+	body.SubscriptionGroup.MemberIds = memberIDs
+
+	req, err := s.client.NewRequest("POST", "subscription_groups", body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gw := new(SubscriptionGroupWrapper)
+	resp, err := s.client.Do(ctx, req, gw)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return gw.SubscriptionGroup, resp, nil
+}
+
+// Get fetches a subscription group, including its current billing amount and
+// account balances.
+//
+// Chargify API docs: https://reference.chargify.com/v1/subscription-groups/read-subscription-group
+func (s *SubscriptionGroupsService) Get(ctx context.Context, uid string) (*SubscriptionGroup, *Response, error) {
+	ctx = withOperationName(ctx, "SubscriptionGroups.Get")
+
+	u := fmt.Sprintf("subscription_groups/%s?include[]=current_billing_amount_in_cents", uid)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	group := new(SubscriptionGroup)
+	resp, err := s.client.Do(ctx, req, group)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return group, resp, nil
+}
+
+// GetBySubscription fetches the group a subscription belongs to.
+//
+// Chargify API docs: https://reference.chargify.com/v1/subscription-groups/find-subscription-group
+func (s *SubscriptionGroupsService) GetBySubscription(ctx context.Context, subscriptionID int) (*SubscriptionGroup, *Response, error) {
+	ctx = withOperationName(ctx, "SubscriptionGroups.GetBySubscription")
+
+	u := fmt.Sprintf("subscription_groups/lookup?subscription_id=%d", subscriptionID)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	group := new(SubscriptionGroup)
+	resp, err := s.client.Do(ctx, req, group)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return group, resp, nil
+}
+
+// Members fetches every subscription of a group.
+func (s *SubscriptionGroupsService) Members(ctx context.Context, uid string) ([]*Subscription, *Response, error) {
+	ctx = withOperationName(ctx, "SubscriptionGroups.Members")
+
+	group, resp, err := s.Get(ctx, uid)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var members []*Subscription
+	for _, id := range group.SubscriptionIds {
+		sub, resp, err := s.client.Subscriptions.Get(ctx, id)
+		if err != nil {
+			return nil, resp, err
+		}
+		members = append(members, sub)
+	}
+	return members, resp, nil
+}
+
+// AddMember adds a subscription to the group of the target subscription.
+//
+// Chargify API docs: https://reference.chargify.com/v1/subscription-groups/add-subscription-to-a-group
+func (s *SubscriptionGroupsService) AddMember(ctx context.Context, subscriptionID, targetSubscriptionID int, billing *GroupMembershipBilling) (*SubscriptionGroup, *Response, error) {
+	ctx = withOperationName(ctx, "SubscriptionGroups.AddMember")
+
+	type target struct {
+		Type string `json:"type"`
+		Id   int    `json:"id"`
+	}
+	body := struct {
+		Group struct {
+			Target  target                  `json:"target"`
+			Billing *GroupMembershipBilling `json:"billing,omitempty"`
+		} `json:"group"`
+	}{}
+	body.Group.Target = target{Type: "subscription", Id: targetSubscriptionID}
+	body.Group.Billing = billing
+
+	u := fmt.Sprintf("subscriptions/%d/group", subscriptionID)
+	req, err := s.client.NewRequest("POST", u, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gw := new(SubscriptionGroupWrapper)
+	resp, err := s.client.Do(ctx, req, gw)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return gw.SubscriptionGroup, resp, nil
+}
+
+// RemoveMember removes a subscription from its group.
+//
+// Chargify API docs: https://reference.chargify.com/v1/subscription-groups/remove-subscription-from-group
+func (s *SubscriptionGroupsService) RemoveMember(ctx context.Context, subscriptionID int) (*Response, error) {
+	ctx = withOperationName(ctx, "SubscriptionGroups.RemoveMember")
+
+	u := fmt.Sprintf("subscriptions/%d/group", subscriptionID)
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// Signup creates a customer and a group of subscriptions for them in a single
+// request.
+//
+// Chargify API docs: https://reference.chargify.com/v1/subscription-groups/subscription-group-signup
+func (s *SubscriptionGroupsService) Signup(ctx context.Context, signup *SubscriptionGroupSignup) (*SubscriptionGroup, *Response, error) {
+	ctx = withOperationName(ctx, "SubscriptionGroups.Signup")
+
+	type signupItem struct {
+		*Subscription
+		Primary bool `json:"primary,omitempty"`
+	}
+	items := make([]signupItem, len(signup.Subscriptions))
+	for i, sub := range signup.Subscriptions {
+		items[i] = signupItem{Subscription: sub, Primary: i == 0}
+	}
+	body := struct {
+		SubscriptionGroup struct {
+			*SubscriptionGroupSignup
+			Subscriptions []signupItem `json:"subscriptions"`
+		} `json:"subscription_group"`
+	}{}
+	body.SubscriptionGroup.SubscriptionGroupSignup = signup
+	body.SubscriptionGroup.Subscriptions = items
+
+	req, err := s.client.NewRequest("POST", "subscription_groups/signup", body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gw := new(SubscriptionGroupWrapper)
+	resp, err := s.client.Do(ctx, req, gw)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return gw.SubscriptionGroup, resp, nil
+}
@@ -0,0 +1,186 @@
+package chargify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSubscriptionGroupsService_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscription_groups", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), `{"subscription_group":{"subscription_id":1,"member_ids":[2,3]}}`+"\n"; got != want {
+			t.Errorf("Request body = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `{"subscription_group": {"customer_id":9,"payment_profile":{"id":4,"masked_card_number":"XXXX-XXXX-XXXX-1"},"subscription_ids":[1,2,3]}}`)
+	})
+
+	group, _, err := client.SubscriptionGroups.Create(context.Background(), 1, []int{2, 3})
+	if err != nil {
+		t.Errorf("SubscriptionGroups.Create returned error: %v", err)
+	}
+
+	want := &SubscriptionGroup{
+		CustomerId:      9,
+		PaymentProfile:  &CreditCard{Id: 4, MaskedCardNumber: "XXXX-XXXX-XXXX-1"},
+		SubscriptionIds: []int{1, 2, 3},
+	}
+	if !reflect.DeepEqual(group, want) {
+		t.Errorf("SubscriptionGroups.Create returned %+v, want %+v", group, want)
+	}
+}
+
+func TestSubscriptionGroupsService_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscription_groups/grp_123", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.Query().Get("include[]"), "current_billing_amount_in_cents"; got != want {
+			t.Errorf("SubscriptionGroups.Get include is %q, want %q", got, want)
+		}
+		fmt.Fprint(w, `{
+			"uid": "grp_123",
+			"subscription_ids": [1,2],
+			"primary_subscription_id": 1,
+			"current_billing_amount_in_cents": 6450,
+			"account_balances": {
+				"prepayments": {"balance_in_cents": 0},
+				"service_credits": {"balance_in_cents": 500},
+				"open_invoices": {"balance_in_cents": 2450}
+			}
+		}`)
+	})
+
+	group, _, err := client.SubscriptionGroups.Get(context.Background(), "grp_123")
+	if err != nil {
+		t.Errorf("SubscriptionGroups.Get returned error: %v", err)
+	}
+
+	want := &SubscriptionGroup{
+		Uid:                         "grp_123",
+		SubscriptionIds:             []int{1, 2},
+		PrimarySubscriptionId:       1,
+		CurrentBillingAmountInCents: 6450,
+		AccountBalances: &SubscriptionGroupBalances{
+			Prepayments:    &AccountBalance{},
+			ServiceCredits: &AccountBalance{BalanceInCents: 500},
+			OpenInvoices:   &AccountBalance{BalanceInCents: 2450},
+		},
+	}
+	if !reflect.DeepEqual(group, want) {
+		t.Errorf("SubscriptionGroups.Get returned %+v, want %+v", group, want)
+	}
+}
+
+func TestSubscriptionGroupsService_Members(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscription_groups/grp_123", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"uid":"grp_123","subscription_ids":[14900541]}`)
+	})
+	mux.HandleFunc("/subscriptions/14900541", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, testSubJSON("active"))
+	})
+
+	members, _, err := client.SubscriptionGroups.Members(context.Background(), "grp_123")
+	if err != nil {
+		t.Errorf("SubscriptionGroups.Members returned error: %v", err)
+	}
+
+	if len(members) != 1 || members[0].Id != 14900541 {
+		t.Errorf("SubscriptionGroups.Members returned %+v, want subscription 14900541", members)
+	}
+}
+
+func TestSubscriptionGroupsService_AddMember(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/2/group", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), `{"group":{"target":{"type":"subscription","id":1},"billing":{"prorate":true}}}`+"\n"; got != want {
+			t.Errorf("Request body = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `{"subscription_group": {"subscription_ids":[1,2]}}`)
+	})
+
+	group, _, err := client.SubscriptionGroups.AddMember(context.Background(), 2, 1, &GroupMembershipBilling{Prorate: true})
+	if err != nil {
+		t.Errorf("SubscriptionGroups.AddMember returned error: %v", err)
+	}
+
+	want := &SubscriptionGroup{SubscriptionIds: []int{1, 2}}
+	if !reflect.DeepEqual(group, want) {
+		t.Errorf("SubscriptionGroups.AddMember returned %+v, want %+v", group, want)
+	}
+}
+
+func TestSubscriptionGroupsService_RemoveMember(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/2/group", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if _, err := client.SubscriptionGroups.RemoveMember(context.Background(), 2); err != nil {
+		t.Errorf("SubscriptionGroups.RemoveMember returned error: %v", err)
+	}
+}
+
+func TestSubscriptionGroupsService_Signup(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscription_groups/signup", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		var v struct {
+			SubscriptionGroup struct {
+				PayerAttributes *Customer `json:"payer_attributes"`
+				Subscriptions   []struct {
+					ProductHandle string `json:"product_handle"`
+					Primary       bool   `json:"primary"`
+				} `json:"subscriptions"`
+			} `json:"subscription_group"`
+		}
+		json.NewDecoder(r.Body).Decode(&v)
+		if got := v.SubscriptionGroup.PayerAttributes; got == nil || got.Reference != "JQPUBLIC" {
+			t.Errorf("Request payer_attributes = %+v, want reference JQPUBLIC", got)
+		}
+		subs := v.SubscriptionGroup.Subscriptions
+		if len(subs) != 2 || subs[0].ProductHandle != "basic" || !subs[0].Primary || subs[1].Primary {
+			t.Errorf("Request subscriptions = %+v, want basic as primary and addon", subs)
+		}
+		fmt.Fprint(w, `{"subscription_group": {"uid":"grp_123","primary_subscription_id":1,"subscriptions":[{"id":1,"product_handle":"basic"},{"id":2,"product_handle":"addon"}]}}`)
+	})
+
+	signup := &SubscriptionGroupSignup{
+		PayerAttributes: &Customer{FirstName: "Amelia", Reference: "JQPUBLIC"},
+		Subscriptions:   []*Subscription{{ProductHandle: "basic"}, {ProductHandle: "addon"}},
+	}
+	group, _, err := client.SubscriptionGroups.Signup(context.Background(), signup)
+	if err != nil {
+		t.Errorf("SubscriptionGroups.Signup returned error: %v", err)
+	}
+
+	want := &SubscriptionGroup{
+		Uid:                   "grp_123",
+		PrimarySubscriptionId: 1,
+		Subscriptions:         []*Subscription{{Id: 1, ProductHandle: "basic"}, {Id: 2, ProductHandle: "addon"}},
+	}
+	if !reflect.DeepEqual(group, want) {
+		t.Errorf("SubscriptionGroups.Signup returned %+v, want %+v", group, want)
+	}
+}
@@ -0,0 +1,144 @@
+package chargify
+
+import (
+	"context"
+	"fmt"
+)
+
+type NoteWrapper struct {
+	Note *Note `json:"note"`
+}
+
+// Note is a free form annotation on a subscription. Sticky notes are pinned
+// to the top of the subscription's page in the Chargify admin.
+type Note struct {
+	Id             int            `json:"id,omitempty"`
+	Body           string         `json:"body,omitempty"`
+	SubscriptionId int            `json:"subscription_id,omitempty"`
+	CreatedAt      *FormattedTime `json:"created_at,omitempty"`
+	UpdatedAt      *FormattedTime `json:"updated_at,omitempty"`
+	Sticky         bool           `json:"sticky,omitempty"`
+}
+
+type SubscriptionNotesService service
+
+// Create adds a note to a subscription.
+//
+// Chargify API docs: https://reference.chargify.com/v1/subscriptions-notes/create-note
+func (s *SubscriptionNotesService) Create(ctx context.Context, subscriptionID int, note *Note) (*Note, *Response, error) {
+	ctx = withOperationName(ctx, "SubscriptionNotes.Create")
+
+	u := fmt.Sprintf("subscriptions/%d/notes", subscriptionID)
+	req, err := s.client.NewRequest("POST", u, NoteWrapper{note})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nw := new(NoteWrapper)
+	resp, err := s.client.Do(ctx, req, nw)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return nw.Note, resp, nil
+}
+
+// List fetches the notes of a subscription.
+//
+// Chargify API docs: https://reference.chargify.com/v1/subscriptions-notes/read-notes
+func (s *SubscriptionNotesService) List(ctx context.Context, subscriptionID int, opt *ListOptions) ([]*Note, *Response, error) {
+	ctx = withOperationName(ctx, "SubscriptionNotes.List")
+
+	u, err := addOptions(fmt.Sprintf("subscriptions/%d/notes", subscriptionID), opt)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var wrappers []*NoteWrapper
+	resp, err := s.client.Do(ctx, req, &wrappers)
+	if err != nil {
+		return nil, resp, err
+	}
+	var notes []*Note
+	for _, n := range wrappers {
+		notes = append(notes, n.Note)
+	}
+	resp.setPageValues(opt, len(notes))
+	return notes, resp, nil
+}
+
+// Get fetches a single note of a subscription.
+//
+// Chargify API docs: https://reference.chargify.com/v1/subscriptions-notes/read-note
+func (s *SubscriptionNotesService) Get(ctx context.Context, subscriptionID, noteID int) (*Note, *Response, error) {
+	ctx = withOperationName(ctx, "SubscriptionNotes.Get")
+
+	u := fmt.Sprintf("subscriptions/%d/notes/%d", subscriptionID, noteID)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nw := new(NoteWrapper)
+	resp, err := s.client.Do(ctx, req, nw)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return nw.Note, resp, nil
+}
+
+// NoteUpdate holds the changes made to a note by
+// SubscriptionNotesService.Update. Unset fields are left unchanged.
+type NoteUpdate struct {
+	Body   Optional[string] `json:"body,omitzero"`
+	Sticky Optional[bool]   `json:"sticky,omitzero"`
+}
+
+func (u NoteUpdate) MarshalJSON() ([]byte, error) {
+	return marshalUpdate(u)
+}
+
+// Update changes the body or sticky flag of a note.
+//
+// Chargify API docs: https://reference.chargify.com/v1/subscriptions-notes/update-note
+func (s *SubscriptionNotesService) Update(ctx context.Context, subscriptionID, noteID int, update *NoteUpdate) (*Note, *Response, error) {
+	ctx = withOperationName(ctx, "SubscriptionNotes.Update")
+
+	body := struct {
+		Note *NoteUpdate `json:"note"`
+	}{update}
+
+	u := fmt.Sprintf("subscriptions/%d/notes/%d", subscriptionID, noteID)
+	req, err := s.client.NewRequest("PUT", u, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nw := new(NoteWrapper)
+	resp, err := s.client.Do(ctx, req, nw)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return nw.Note, resp, nil
+}
+
+// Delete removes a note from a subscription.
+//
+// Chargify API docs: https://reference.chargify.com/v1/subscriptions-notes/delete-note
+func (s *SubscriptionNotesService) Delete(ctx context.Context, subscriptionID, noteID int) (*Response, error) {
+	ctx = withOperationName(ctx, "SubscriptionNotes.Delete")
+
+	u := fmt.Sprintf("subscriptions/%d/notes/%d", subscriptionID, noteID)
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
@@ -0,0 +1,119 @@
+package chargify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSubscriptionNotesService_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	input := &Note{Body: "Called about an invoice", Sticky: true}
+
+	mux.HandleFunc("/subscriptions/14900541/notes", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		v := new(NoteWrapper)
+		json.NewDecoder(r.Body).Decode(v)
+		if !reflect.DeepEqual(v.Note, input) {
+			t.Errorf("Request body = %+v, want %+v", v.Note, input)
+		}
+		fmt.Fprint(w, `{"note": {"id":1,"body":"Called about an invoice","subscription_id":14900541,"sticky":true}}`)
+	})
+
+	note, _, err := client.SubscriptionNotes.Create(context.Background(), 14900541, input)
+	if err != nil {
+		t.Errorf("SubscriptionNotes.Create returned error: %v", err)
+	}
+
+	want := &Note{Id: 1, Body: "Called about an invoice", SubscriptionId: 14900541, Sticky: true}
+	if !reflect.DeepEqual(note, want) {
+		t.Errorf("SubscriptionNotes.Create returned %+v, want %+v", note, want)
+	}
+}
+
+func TestSubscriptionNotesService_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/14900541/notes", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got, want := r.URL.RawQuery, "page=2"; got != want {
+			t.Errorf("SubscriptionNotes.List query is %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `[{"note": {"id":1}},{"note": {"id":2}}]`)
+	})
+
+	notes, _, err := client.SubscriptionNotes.List(context.Background(), 14900541, &ListOptions{Page: 2})
+	if err != nil {
+		t.Errorf("SubscriptionNotes.List returned error: %v", err)
+	}
+
+	want := []*Note{{Id: 1}, {Id: 2}}
+	if !reflect.DeepEqual(notes, want) {
+		t.Errorf("SubscriptionNotes.List returned %+v, want %+v", notes, want)
+	}
+}
+
+func TestSubscriptionNotesService_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/14900541/notes/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"note": {"id":1,"body":"hi"}}`)
+	})
+
+	note, _, err := client.SubscriptionNotes.Get(context.Background(), 14900541, 1)
+	if err != nil {
+		t.Errorf("SubscriptionNotes.Get returned error: %v", err)
+	}
+
+	want := &Note{Id: 1, Body: "hi"}
+	if !reflect.DeepEqual(note, want) {
+		t.Errorf("SubscriptionNotes.Get returned %+v, want %+v", note, want)
+	}
+}
+
+func TestSubscriptionNotesService_Update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/14900541/notes/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), `{"note":{"sticky":false}}`+"\n"; got != want {
+			t.Errorf("Request body = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `{"note": {"id":1,"body":"updated"}}`)
+	})
+
+	note, _, err := client.SubscriptionNotes.Update(context.Background(), 14900541, 1, &NoteUpdate{Sticky: Set(false)})
+	if err != nil {
+		t.Errorf("SubscriptionNotes.Update returned error: %v", err)
+	}
+
+	want := &Note{Id: 1, Body: "updated"}
+	if !reflect.DeepEqual(note, want) {
+		t.Errorf("SubscriptionNotes.Update returned %+v, want %+v", note, want)
+	}
+}
+
+func TestSubscriptionNotesService_Delete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/14900541/notes/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+	})
+
+	_, err := client.SubscriptionNotes.Delete(context.Background(), 14900541, 1)
+	if err != nil {
+		t.Errorf("SubscriptionNotes.Delete returned error: %v", err)
+	}
+}
@@ -2,7 +2,13 @@ package chargify
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
 )
 
 type SubscriptionWrapper struct {
@@ -10,52 +16,129 @@ type SubscriptionWrapper struct {
 }
 
 type Subscription struct {
-	Id                          int            `json:"id,omitempty"`
-	State                       string         `json:"state,omitempty"`
-	TrialStartedAt              *FormattedTime `json:"trial_started_at,omitempty"`
-	Customer                    *Customer      `json:"customer,omitempty"`
-	CustomerAttributes          *Customer      `json:"customer_attributes,omitempty"`
-	Product                     *Product       `json:"product,omitempty"`
-	ProductHandle               string         `json:"product_handle,omitempty"`
-	CreditCard                  *CreditCard    `json:"credit_card,omitempty"`
-	TrialEndedAt                *FormattedTime `json:"trial_ended_at,omitempty"`
-	ActivatedAt                 *FormattedTime `json:"activated_at,omitempty"`
-	CreatedAt                   *FormattedTime `json:"created_at,omitempty"`
-	UpdatedAt                   *FormattedTime `json:"updated_at,omitempty"`
-	ExpiresAt                   *FormattedTime `json:"expires_at,omitempty"`
-	PreviousExpiresAt           *FormattedTime `json:"previous_expires_at,omitempty"`
-	BalanceInCents              int            `json:"balance_in_cents,omitempty"`
-	CurrentPeriodEndsAt         *FormattedTime `json:"current_period_ends_at,omitempty"`
-	NextAssessmentAt            *FormattedTime `json:"next_assessment_at,omitempty"`
-	CanceledAt                  *FormattedTime `json:"canceled_at,omitempty"`
-	CancellationMessage         string         `json:"cancellation_message,omitempty"`
-	NextProductId               int            `json:"next_product_id,omitempty"`
-	CancelAtEndOfPeriod         bool           `json:"cancel_at_end_of_period,omitempty"`
-	PaymentCollectionMethod     string         `json:"payment_collection_method,omitempty"`
-	SnapDay                     string         `json:"snap_day,omitempty"`
-	CancellationMethod          string         `json:"cancellation_method,omitempty"`
-	CurrentPeriodStartedAt      *FormattedTime `json:"current_period_started_at,omitempty"`
-	PreviousState               string         `json:"previous_state,omitempty"`
-	SignupPaymentId             int            `json:"signup_payment_id,omitempty"`
-	SignupRevenue               float32        `json:"signup_revenue,omitempty,string"`
-	DelayedCancelAt             *FormattedTime `json:"delayed_cancel_at,omitempty"`
-	CouponCode                  string         `json:"coupon_code,omitempty"`
-	TotalRevenueInCents         int            `json:"total_revenue_in_cents,omitempty"`
-	ProductPriceInCents         int            `json:"product_price_in_cents,omitempty"`
-	ProductVersionNumber        int            `json:"product_version_number,omitempty"`
-	PaymentType                 string         `json:"payment_type,omitempty"`
-	ReferralCode                string         `json:"referral_code,omitempty"`
-	CouponUseCount              int            `json:"coupon_use_count,omitempty"`
-	CouponUsesAllowed           int            `json:"coupon_uses_allowed,omitempty"`
-	CurrentBillingAmountInCents int            `json:"current_billing_amount_in_cents,omitempty"`
+	Id                          int                     `json:"id,omitempty"`
+	State                       SubscriptionState       `json:"state,omitempty"`
+	TrialStartedAt              *FormattedTime          `json:"trial_started_at,omitempty"`
+	Customer                    *Customer               `json:"customer,omitempty"`
+	CustomerAttributes          *Customer               `json:"customer_attributes,omitempty"`
+	CustomerReference           string                  `json:"customer_reference,omitempty"`
+	Product                     *Product                `json:"product,omitempty"`
+	ProductHandle               string                  `json:"product_handle,omitempty"`
+	CreditCard                  *CreditCard             `json:"credit_card,omitempty"`
+	CreditCardAttributes        *CreditCard             `json:"credit_card_attributes,omitempty"`
+	TrialEndedAt                *FormattedTime          `json:"trial_ended_at,omitempty"`
+	ActivatedAt                 *FormattedTime          `json:"activated_at,omitempty"`
+	CreatedAt                   *FormattedTime          `json:"created_at,omitempty"`
+	UpdatedAt                   *FormattedTime          `json:"updated_at,omitempty"`
+	ExpiresAt                   *FormattedTime          `json:"expires_at,omitempty"`
+	PreviousExpiresAt           *FormattedTime          `json:"previous_expires_at,omitempty"`
+	BalanceInCents              int                     `json:"balance_in_cents,omitempty"`
+	CurrentPeriodEndsAt         *FormattedTime          `json:"current_period_ends_at,omitempty"`
+	NextAssessmentAt            *FormattedTime          `json:"next_assessment_at,omitempty"`
+	CanceledAt                  *FormattedTime          `json:"canceled_at,omitempty"`
+	CancellationMessage         string                  `json:"cancellation_message,omitempty"`
+	NextProductId               int                     `json:"next_product_id,omitempty"`
+	CancelAtEndOfPeriod         bool                    `json:"cancel_at_end_of_period,omitempty"`
+	PaymentCollectionMethod     PaymentCollectionMethod `json:"payment_collection_method,omitempty"`
+	SnapDay                     string                  `json:"snap_day,omitempty"`
+	CancellationMethod          CancellationMethod      `json:"cancellation_method,omitempty"`
+	CurrentPeriodStartedAt      *FormattedTime          `json:"current_period_started_at,omitempty"`
+	PreviousState               SubscriptionState       `json:"previous_state,omitempty"`
+	SignupPaymentId             int                     `json:"signup_payment_id,omitempty"`
+	SignupRevenue               float32                 `json:"signup_revenue,omitempty,string"`
+	DelayedCancelAt             *FormattedTime          `json:"delayed_cancel_at,omitempty"`
+	CouponCode                  string                  `json:"coupon_code,omitempty"`
+	TotalRevenueInCents         int                     `json:"total_revenue_in_cents,omitempty"`
+	ProductPriceInCents         int                     `json:"product_price_in_cents,omitempty"`
+	ProductVersionNumber        int                     `json:"product_version_number,omitempty"`
+	PaymentType                 PaymentType             `json:"payment_type,omitempty"`
+	ReferralCode                string                  `json:"referral_code,omitempty"`
+	CouponUseCount              int                     `json:"coupon_use_count,omitempty"`
+	CouponUsesAllowed           int                     `json:"coupon_uses_allowed,omitempty"`
+	CurrentBillingAmountInCents int                     `json:"current_billing_amount_in_cents,omitempty"`
+
+	// Ref is the referral code that brought in the customer, sent when
+	// creating a subscription, while ReferralCode is the subscription's own.
+	Ref string `json:"ref,omitempty"`
+
+	// Extra holds the fields of the API response with no field above.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// SubscriptionListOptions specifies the optional parameters to the
+// SubscriptionsService.List method.
+type SubscriptionListOptions struct {
+	// State limits the results to subscriptions in the given state.
+	State SubscriptionState `url:"state,omitempty"`
+
+	// Product limits the results to subscriptions to the given product id.
+	Product int `url:"product,omitempty"`
+
+	// Coupon limits the results to subscriptions using the given coupon id.
+	Coupon int `url:"coupon,omitempty"`
+
+	// DateField is the timestamp the date range applies to. Can be one of
+	// "current_period_ends_at", "current_period_starts_at", "created_at",
+	// "activated_at", "canceled_at", "expires_at", "trial_started_at",
+	// "trial_ended_at" or "updated_at".
+	DateField string `url:"date_field,omitempty"`
+
+	// StartDate and EndDate restrict the results to subscriptions whose
+	// DateField falls on or between the given days.
+	StartDate time.Time `url:"start_date,omitempty" layout:"2006-01-02"`
+	EndDate   time.Time `url:"end_date,omitempty" layout:"2006-01-02"`
+
+	// Metadata limits the results to subscriptions whose custom fields
+	// have the given values.
+	Metadata MetadataFilter `url:"metadata,omitempty"`
+
+	ListOptions
 }
 
+// SubscriptionsService handles the subscriptions of a site. Actions changing
+// a subscription's lifecycle can be checked against its last known state
+// before they are sent; see WithTransitionChecks.
 type SubscriptionsService service
 
+// List fetches the subscriptions for the site.
+//
+// Chargify API docs: https://reference.chargify.com/v1/subscriptions/list-subscriptions
+func (s *SubscriptionsService) List(ctx context.Context, opt *SubscriptionListOptions) ([]*Subscription, *Response, error) {
+	ctx = withOperationName(ctx, "Subscriptions.List")
+
+	u, err := addOptions("subscriptions", opt)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var wrappers []*SubscriptionWrapper
+	resp, err := s.client.Do(ctx, req, &wrappers)
+	if err != nil {
+		return nil, resp, err
+	}
+	var subs []*Subscription
+	for _, sw := range wrappers {
+		subs = append(subs, sw.Subscription)
+	}
+	s.client.states.observe(subs...)
+	if opt != nil {
+		resp.setPageValues(&opt.ListOptions, len(subs))
+	} else {
+		resp.setPageValues(nil, len(subs))
+	}
+	return subs, resp, nil
+}
+
 // Create creates a new subscription.
 //
 // Chargify API docs: https://reference.chargify.com/v1/subscriptions/create-subscription
 func (svc *SubscriptionsService) Create(ctx context.Context, sub *Subscription) (*Subscription, *Response, error) {
+	ctx = withOperationName(ctx, "Subscriptions.Create")
+
 	u := "/subscriptions"
 
 	sw := SubscriptionWrapper{sub}
@@ -69,14 +152,160 @@ func (svc *SubscriptionsService) Create(ctx context.Context, sub *Subscription)
 	if err != nil {
 		return nil, resp, err
 	}
+	svc.client.states.observe(swr.Subscription)
 
 	return swr.Subscription, resp, nil
 }
 
+// createRetryBackoff is how long CreateOnce waits before its first retry. The
+// wait doubles with every further attempt.
+var createRetryBackoff = time.Second
+
+// CreateOnce creates a new subscription, retrying up to attempts times in
+// total while Chargify cannot be reached, responds with a server error or
+// limits the rate of requests. Other errors are returned at once. Every
+// attempt is sent with the same idempotency key, taken from ctx or generated
+// once.
+//
+// Before the first attempt, CreateOnce notes the subscriptions sub's customer
+// already has. Before each retry it looks for a subscription to the same
+// product that is not one of them; if an earlier attempt did succeed, that
+// subscription is returned instead of creating a duplicate. The check
+// requires sub to identify the customer by reference and the product by
+// handle, and is skipped if the existing subscriptions cannot be listed.
+func (s *SubscriptionsService) CreateOnce(ctx context.Context, sub *Subscription, attempts int) (*Subscription, *Response, error) {
+	ctx = withOperationName(ctx, "Subscriptions.CreateOnce")
+
+	if _, ok := IdempotencyKeyFromContext(ctx); !ok {
+		ctx = WithIdempotencyKey(ctx, NewIdempotencyKey())
+	}
+	known, dedupe := s.subscriptionIDs(ctx, sub)
+	backoff := createRetryBackoff
+
+	// Retries are left to the loop below, which checks for a subscription
+	// created by an earlier attempt first.
+	createCtx := context.WithValue(ctx, noRetryContextKey{}, true)
+	for attempt := 1; ; attempt++ {
+		created, resp, err := s.Create(createCtx, sub)
+		if err == nil || attempt >= attempts || !retryableCreateError(err) {
+			return created, resp, err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, resp, ctx.Err()
+		}
+		backoff *= 2
+
+		if !dedupe {
+			continue
+		}
+		existing, _, lookupErr := s.findCreated(ctx, sub, known)
+		if lookupErr == nil && existing != nil {
+			return existing, resp, nil
+		}
+	}
+}
+
+// retryableCreateError reports whether a failed create may be retried: the
+// request never got a response, the response was a server error or the rate
+// of requests was limited.
+func retryableCreateError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var errResp *ErrorResponse
+	if errors.As(err, &errResp) {
+		return errResp.Response.StatusCode >= http.StatusInternalServerError
+	}
+	var rateErr *RateLimitError
+	if errors.As(err, &rateErr) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// createTarget returns the customer reference and product handle identifying
+// the subscription sub creates, or empty strings if sub lacks either.
+func createTarget(sub *Subscription) (reference, handle string) {
+	reference = sub.CustomerReference
+	if reference == "" && sub.CustomerAttributes != nil {
+		reference = sub.CustomerAttributes.Reference
+	}
+	handle = sub.ProductHandle
+	if handle == "" && sub.Product != nil {
+		handle = sub.Product.Handle
+	}
+	if reference == "" || handle == "" {
+		return "", ""
+	}
+	return reference, handle
+}
+
+// subscriptionIDs returns the ids of the subscriptions sub's customer has,
+// and whether they could be listed. A customer that does not exist yet has
+// none.
+func (s *SubscriptionsService) subscriptionIDs(ctx context.Context, sub *Subscription) (map[int]bool, bool) {
+	reference, _ := createTarget(sub)
+	if reference == "" {
+		return nil, false
+	}
+
+	ids := make(map[int]bool)
+	customer, _, err := s.client.Customers.Lookup(ctx, reference)
+	if errResp, ok := err.(*ErrorResponse); ok && errResp.Response.StatusCode == http.StatusNotFound {
+		return ids, true
+	}
+	if err != nil {
+		return nil, false
+	}
+	subs, _, err := s.client.Customers.ListSubscriptions(ctx, customer.Id)
+	if err != nil {
+		return nil, false
+	}
+	for _, existing := range subs {
+		ids[existing.Id] = true
+	}
+	return ids, true
+}
+
+// findCreated looks for a subscription of sub's customer to sub's product
+// that is not one of the known subscriptions.
+func (s *SubscriptionsService) findCreated(ctx context.Context, sub *Subscription, known map[int]bool) (*Subscription, *Response, error) {
+	reference, handle := createTarget(sub)
+	if reference == "" {
+		return nil, nil, nil
+	}
+
+	customer, resp, err := s.client.Customers.Lookup(ctx, reference)
+	if err != nil {
+		if errResp, ok := err.(*ErrorResponse); ok && errResp.Response.StatusCode == http.StatusNotFound {
+			return nil, resp, nil
+		}
+		return nil, resp, err
+	}
+	subs, resp, err := s.client.Customers.ListSubscriptions(ctx, customer.Id)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	for _, existing := range subs {
+		if known[existing.Id] || existing.Product == nil || existing.Product.Handle != handle {
+			continue
+		}
+		return existing, resp, nil
+	}
+	return nil, resp, nil
+}
+
 // Get fetches a subscription.
 //
 // Chargify API docs: https://reference.chargify.com/v1/subscriptions/read-subscription
 func (s *SubscriptionsService) Get(ctx context.Context, id int) (*Subscription, *Response, error) {
+	ctx = withOperationName(ctx, "Subscriptions.Get")
+
 	u := fmt.Sprintf("subscriptions/%d", id)
 	req, err := s.client.NewRequest("GET", u, nil)
 	if err != nil {
@@ -88,16 +317,103 @@ func (s *SubscriptionsService) Get(ctx context.Context, id int) (*Subscription,
 	if err != nil {
 		return nil, resp, err
 	}
+	s.client.states.observe(sw.Subscription)
+
+	return sw.Subscription, resp, nil
+}
+
+// SubscriptionUpdate holds the changes made to a subscription by
+// SubscriptionsService.Update. Unset fields are left unchanged.
+type SubscriptionUpdate struct {
+	ProductHandle                     Optional[string]                  `json:"product_handle,omitzero"`
+	ProductId                         Optional[int]                     `json:"product_id,omitzero"`
+	ProductChangeDelayed              Optional[bool]                    `json:"product_change_delayed,omitzero"`
+	NextProductId                     Optional[int]                     `json:"next_product_id,omitzero"`
+	NextBillingAt                     Optional[time.Time]               `json:"next_billing_at,omitzero"`
+	SnapDay                           Optional[string]                  `json:"snap_day,omitzero"`
+	PaymentCollectionMethod           Optional[PaymentCollectionMethod] `json:"payment_collection_method,omitzero"`
+	ReceivesInvoiceEmails             Optional[bool]                    `json:"receives_invoice_emails,omitzero"`
+	NetTerms                          Optional[int]                     `json:"net_terms,omitzero"`
+	Reference                         Optional[string]                  `json:"reference,omitzero"`
+	DunningCommunicationDelayEnabled  Optional[bool]                    `json:"dunning_communication_delay_enabled,omitzero"`
+	DunningCommunicationDelayTimeZone Optional[string]                  `json:"dunning_communication_delay_time_zone,omitzero"`
+	CancelAtEndOfPeriod               Optional[bool]                    `json:"cancel_at_end_of_period,omitzero"`
+	BalanceInCents                    Optional[int]                     `json:"balance_in_cents,omitzero"`
+	CreditCardAttributes              *CreditCard                       `json:"credit_card_attributes,omitempty"`
+}
+
+func (u SubscriptionUpdate) MarshalJSON() ([]byte, error) {
+	return marshalUpdate(u)
+}
+
+// Update changes the settings of a subscription.
+//
+// Chargify API docs: https://reference.chargify.com/v1/subscriptions/update-subscription
+func (s *SubscriptionsService) Update(ctx context.Context, id int, update *SubscriptionUpdate) (*Subscription, *Response, error) {
+	ctx = withOperationName(ctx, "Subscriptions.Update")
+
+	body := struct {
+		Subscription *SubscriptionUpdate `json:"subscription"`
+	}{update}
+
+	u := fmt.Sprintf("subscriptions/%d", id)
+	req, err := s.client.NewRequest("PUT", u, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sw := new(SubscriptionWrapper)
+	resp, err := s.client.Do(ctx, req, sw)
+	if err != nil {
+		return nil, resp, err
+	}
+	s.client.states.observe(sw.Subscription)
 
 	return sw.Subscription, resp, nil
 }
 
+// CancellationOptions records why a subscription is canceled, for
+// SubscriptionsService.DestroyWithOptions and
+// SubscriptionsService.DelayedCancelWithOptions.
+type CancellationOptions struct {
+	// ReasonCode is the code of one of the site's reason codes; see
+	// ReasonCodesService.
+	ReasonCode          string `json:"reason_code,omitempty"`
+	CancellationMessage string `json:"cancellation_message,omitempty"`
+}
+
+// cancellationBody returns the request body canceling with opt, or nil for
+// no options.
+func cancellationBody(opt *CancellationOptions) interface{} {
+	if opt == nil {
+		return nil
+	}
+	return struct {
+		Subscription *CancellationOptions `json:"subscription"`
+	}{opt}
+}
+
 // Destroy cancels a subscription.
 //
 // Chargify API docs: https://reference.chargify.com/v1/subscriptions/cancel-subscription
 func (svc *SubscriptionsService) Destroy(ctx context.Context, id int) (*Subscription, *Response, error) {
+	ctx = withOperationName(ctx, "Subscriptions.Destroy")
+	return svc.DestroyWithOptions(ctx, id, nil)
+}
+
+// DestroyWithOptions cancels a subscription, recording why with opt, which
+// may be nil.
+//
+// Chargify API docs: https://reference.chargify.com/v1/subscriptions/cancel-subscription
+func (svc *SubscriptionsService) DestroyWithOptions(ctx context.Context, id int, opt *CancellationOptions) (*Subscription, *Response, error) {
+	ctx = withOperationName(ctx, "Subscriptions.DestroyWithOptions")
+
+	if err := svc.client.states.check(id, ActionCancel); err != nil {
+		return nil, nil, err
+	}
+
 	u := fmt.Sprintf("subscriptions/%d", id)
-	req, err := svc.client.NewRequest("DELETE", u, nil)
+	req, err := svc.client.NewRequest("DELETE", u, cancellationBody(opt))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -107,6 +423,106 @@ func (svc *SubscriptionsService) Destroy(ctx context.Context, id int) (*Subscrip
 	if err != nil {
 		return nil, resp, err
 	}
+	svc.client.states.observe(swr.Subscription)
 
 	return swr.Subscription, resp, nil
 }
+
+// Migration describes a change of a subscription's product made with
+// SubscriptionsService.Migrate. Either ProductId or ProductHandle must be set.
+// Options left false keep Chargify's defaults.
+type Migration struct {
+	ProductId            int    `json:"product_id,omitempty"`
+	ProductHandle        string `json:"product_handle,omitempty"`
+	ProductPricePointId  int    `json:"product_price_point_id,omitempty"`
+	IncludeTrial         bool   `json:"include_trial,omitempty"`
+	IncludeInitialCharge bool   `json:"include_initial_charge,omitempty"`
+	IncludeCoupons       bool   `json:"include_coupons,omitempty"`
+	PreservePeriod       bool   `json:"preserve_period,omitempty"`
+}
+
+// Migrate moves a subscription to another product immediately, prorating
+// the change.
+//
+// Chargify API docs: https://reference.chargify.com/v1/subscriptions-product-changes-migrations-upgrades-downgrades/migrate-subscription-product
+func (s *SubscriptionsService) Migrate(ctx context.Context, id int, migration *Migration) (*Subscription, *Response, error) {
+	ctx = withOperationName(ctx, "Subscriptions.Migrate")
+
+	if err := s.client.states.check(id, ActionMigrate); err != nil {
+		return nil, nil, err
+	}
+
+	body := struct {
+		Migration *Migration `json:"migration"`
+	}{migration}
+
+	u := fmt.Sprintf("subscriptions/%d/migrations", id)
+	req, err := s.client.NewRequest("POST", u, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sw := new(SubscriptionWrapper)
+	resp, err := s.client.Do(ctx, req, sw)
+	if err != nil {
+		return nil, resp, err
+	}
+	s.client.states.observe(sw.Subscription)
+
+	return sw.Subscription, resp, nil
+}
+
+// DelayedCancel schedules a subscription to be canceled at the end of its
+// current billing period.
+//
+// Chargify API docs: https://reference.chargify.com/v1/subscriptions-cancellations/cancel-subscription-delayed-method
+func (s *SubscriptionsService) DelayedCancel(ctx context.Context, id int) (*Response, error) {
+	ctx = withOperationName(ctx, "Subscriptions.DelayedCancel")
+	return s.DelayedCancelWithOptions(ctx, id, nil)
+}
+
+// DelayedCancelWithOptions schedules a subscription to be canceled at the end
+// of its current billing period, recording why with opt, which may be nil.
+//
+// Chargify API docs: https://reference.chargify.com/v1/subscriptions-cancellations/cancel-subscription-delayed-method
+func (s *SubscriptionsService) DelayedCancelWithOptions(ctx context.Context, id int, opt *CancellationOptions) (*Response, error) {
+	ctx = withOperationName(ctx, "Subscriptions.DelayedCancelWithOptions")
+
+	if err := s.client.states.check(id, ActionDelayedCancel); err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("subscriptions/%d/delayed_cancel", id)
+	req, err := s.client.NewRequest("POST", u, cancellationBody(opt))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// AddCoupon applies a coupon to a subscription.
+//
+// Chargify API docs: https://reference.chargify.com/v1/coupons-editing/add-coupon-to-subscription
+func (s *SubscriptionsService) AddCoupon(ctx context.Context, id int, code string) (*Subscription, *Response, error) {
+	ctx = withOperationName(ctx, "Subscriptions.AddCoupon")
+
+	if err := s.client.states.check(id, ActionAddCoupon); err != nil {
+		return nil, nil, err
+	}
+
+	u := fmt.Sprintf("subscriptions/%d/add_coupon?code=%s", id, url.QueryEscape(code))
+	req, err := s.client.NewRequest("POST", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sw := new(SubscriptionWrapper)
+	resp, err := s.client.Do(ctx, req, sw)
+	if err != nil {
+		return nil, resp, err
+	}
+	s.client.states.observe(sw.Subscription)
+
+	return sw.Subscription, resp, nil
+}
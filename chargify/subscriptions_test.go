@@ -3,10 +3,15 @@ package chargify
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"html/template"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/kylelemons/godebug/pretty"
 )
@@ -161,7 +166,7 @@ func testSubJSON(state string) string {
 
 }
 
-func testSub(state string) *Subscription {
+func testSub(state SubscriptionState) *Subscription {
 	if state == "" {
 		state = "active"
 	}
@@ -358,3 +363,305 @@ func TestSubscriptionsService_Destroy(t *testing.T) {
 		t.Errorf("Products.List diff: (-got +want)\n%s\n", diff)
 	}
 }
+
+func TestSubscriptionsService_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		want := "metadata%5BAccount+id%5D=A-123&state=active"
+		if got := r.URL.RawQuery; got != want {
+			t.Errorf("Subscriptions.List query is %v, want %v", got, want)
+		}
+		fmt.Fprintf(w, "[%s]", testSubJSON("active"))
+	})
+
+	opt := &SubscriptionListOptions{State: "active", Metadata: MetadataFilter{"Account id": "A-123"}}
+	subs, _, err := client.Subscriptions.List(context.Background(), opt)
+	if err != nil {
+		t.Errorf("Subscriptions.List returned error: %v", err)
+	}
+
+	want := []*Subscription{testSub("active")}
+	if diff := pretty.Compare(subs, want); diff != "" {
+		t.Errorf("Subscriptions.List diff: (-got +want)\n%s\n", diff)
+	}
+}
+
+func TestSubscriptionsService_CreateOnce_findsExisting(t *testing.T) {
+	setup()
+	defer teardown()
+	defer func(d time.Duration) { createRetryBackoff = d }(createRetryBackoff)
+	createRetryBackoff = time.Millisecond
+
+	creates := 0
+	mux.HandleFunc("/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		creates++
+		// The subscription is created, but the response is lost.
+		http.Error(w, "Gateway Timeout", http.StatusGatewayTimeout)
+	})
+	mux.HandleFunc("/customers/lookup", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"customer": {"id":14399371,"reference":"JQPUBLIC"}}`)
+	})
+	mux.HandleFunc("/customers/14399371/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		if creates == 0 {
+			fmt.Fprint(w, `[{"subscription": {"id":1,"product":{"handle":"basic"}}}]`)
+			return
+		}
+		fmt.Fprint(w, `[
+			{"subscription": {"id":1,"product":{"handle":"basic"}}},
+			{"subscription": {"id":2,"product":{"handle":"basic"}}}
+		]`)
+	})
+
+	input := &Subscription{CustomerReference: "JQPUBLIC", ProductHandle: "basic"}
+	sub, _, err := client.Subscriptions.CreateOnce(context.Background(), input, 3)
+	if err != nil {
+		t.Fatalf("Subscriptions.CreateOnce returned error: %v", err)
+	}
+	if sub.Id != 2 {
+		t.Errorf("Subscriptions.CreateOnce returned subscription %d, want 2", sub.Id)
+	}
+	if creates != 1 {
+		t.Errorf("Subscriptions.CreateOnce sent %d creates, want 1", creates)
+	}
+}
+
+func TestSubscriptionsService_CreateOnce_ignoresPreexisting(t *testing.T) {
+	setup()
+	defer teardown()
+	defer func(d time.Duration) { createRetryBackoff = d }(createRetryBackoff)
+	createRetryBackoff = time.Millisecond
+
+	creates := 0
+	mux.HandleFunc("/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		creates++
+		if creates == 1 {
+			http.Error(w, "Bad Gateway", http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, testSubJSON("active"))
+	})
+	mux.HandleFunc("/customers/lookup", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"customer": {"id":14399371,"reference":"JQPUBLIC"}}`)
+	})
+	mux.HandleFunc("/customers/14399371/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		// A subscription to the same product created moments before the call
+		// is not the one being created.
+		created := time.Now().Add(-time.Minute).Format(`2006-01-02T15:04:05-07:00`)
+		fmt.Fprintf(w, `[{"subscription": {"id":1,"created_at":%q,"product":{"handle":"basic"}}}]`, created)
+	})
+
+	input := &Subscription{CustomerReference: "JQPUBLIC", ProductHandle: "basic"}
+	sub, _, err := client.Subscriptions.CreateOnce(context.Background(), input, 3)
+	if err != nil {
+		t.Fatalf("Subscriptions.CreateOnce returned error: %v", err)
+	}
+	if sub.Id != 14900541 || creates != 2 {
+		t.Errorf("Subscriptions.CreateOnce returned subscription %d after %d creates, want 14900541 after 2", sub.Id, creates)
+	}
+}
+
+func TestSubscriptionsService_CreateOnce_retries(t *testing.T) {
+	setup()
+	defer teardown()
+	defer func(d time.Duration) { createRetryBackoff = d }(createRetryBackoff)
+	createRetryBackoff = time.Millisecond
+
+	var keys []string
+	mux.HandleFunc("/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(idempotencyKeyHeader))
+		if len(keys) == 1 {
+			http.Error(w, "Bad Gateway", http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, testSubJSON("active"))
+	})
+	mux.HandleFunc("/customers/lookup", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+
+	input := &Subscription{CustomerReference: "JQPUBLIC", ProductHandle: "basic"}
+	sub, _, err := client.Subscriptions.CreateOnce(context.Background(), input, 3)
+	if err != nil {
+		t.Fatalf("Subscriptions.CreateOnce returned error: %v", err)
+	}
+	if sub.Id != 14900541 {
+		t.Errorf("Subscriptions.CreateOnce returned subscription %d, want 14900541", sub.Id)
+	}
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("Subscriptions.CreateOnce sent idempotency keys %q, want the same key twice", keys)
+	}
+}
+
+func TestSubscriptionsService_CreateOnce_clientError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	creates := 0
+	mux.HandleFunc("/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		creates++
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `{"errors": ["Product must be specified"]}`)
+	})
+
+	_, _, err := client.Subscriptions.CreateOnce(context.Background(), &Subscription{}, 3)
+	if err == nil {
+		t.Error("Expected error to be returned.")
+	}
+	if creates != 1 {
+		t.Errorf("Subscriptions.CreateOnce sent %d creates, want 1", creates)
+	}
+}
+
+func TestRetryableCreateError(t *testing.T) {
+	serverErr := &ErrorResponse{Response: &http.Response{StatusCode: http.StatusBadGateway}}
+	clientErr := &ErrorResponse{Response: &http.Response{StatusCode: http.StatusUnprocessableEntity}}
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&url.Error{Op: "Post", URL: "/subscriptions", Err: io.ErrUnexpectedEOF}, true},
+		{serverErr, true},
+		{&RateLimitError{Response: &http.Response{StatusCode: http.StatusTooManyRequests}}, true},
+		{clientErr, false},
+		{&json.SyntaxError{}, false},
+		{&InvalidTransitionError{}, false},
+		{ErrRawCardData, false},
+		{ErrCircuitOpen, false},
+		{context.Canceled, false},
+		{&url.Error{Op: "Post", URL: "/subscriptions", Err: context.DeadlineExceeded}, false},
+	}
+
+	for _, tt := range tests {
+		if got := retryableCreateError(tt.err); got != tt.want {
+			t.Errorf("retryableCreateError(%#v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestSubscriptionsService_Migrate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/14900541/migrations", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), `{"migration":{"product_handle":"gold","preserve_period":true}}`+"\n"; got != want {
+			t.Errorf("Request body = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, testSubJSON("active"))
+	})
+
+	sub, _, err := client.Subscriptions.Migrate(context.Background(), 14900541, &Migration{ProductHandle: "gold", PreservePeriod: true})
+	if err != nil {
+		t.Errorf("Subscriptions.Migrate returned error: %v", err)
+	}
+	if sub == nil || sub.Id != 14900541 {
+		t.Errorf("Subscriptions.Migrate returned %+v, want subscription 14900541", sub)
+	}
+}
+
+func TestSubscriptionsService_DelayedCancel(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/14900541/delayed_cancel", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		fmt.Fprint(w, `{"message": "This subscription will be canceled at the end of the period."}`)
+	})
+
+	if _, err := client.Subscriptions.DelayedCancel(context.Background(), 14900541); err != nil {
+		t.Errorf("Subscriptions.DelayedCancel returned error: %v", err)
+	}
+}
+
+func TestSubscriptionsService_DestroyWithOptions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/14900541", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), `{"subscription":{"reason_code":"PRICE","cancellation_message":"Too expensive"}}`+"\n"; got != want {
+			t.Errorf("Request body = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, testSubJSON("canceled"))
+	})
+
+	opt := &CancellationOptions{ReasonCode: "PRICE", CancellationMessage: "Too expensive"}
+	sub, _, err := client.Subscriptions.DestroyWithOptions(context.Background(), 14900541, opt)
+	if err != nil {
+		t.Errorf("Subscriptions.DestroyWithOptions returned error: %v", err)
+	}
+	if sub == nil || sub.State != StateCanceled {
+		t.Errorf("Subscriptions.DestroyWithOptions returned %+v, want a canceled subscription", sub)
+	}
+}
+
+func TestSubscriptionsService_DelayedCancelWithOptions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/14900541/delayed_cancel", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), `{"subscription":{"reason_code":"PRICE"}}`+"\n"; got != want {
+			t.Errorf("Request body = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `{"message": "This subscription will be canceled at the end of the period."}`)
+	})
+
+	opt := &CancellationOptions{ReasonCode: "PRICE"}
+	if _, err := client.Subscriptions.DelayedCancelWithOptions(context.Background(), 14900541, opt); err != nil {
+		t.Errorf("Subscriptions.DelayedCancelWithOptions returned error: %v", err)
+	}
+}
+
+func TestSubscriptionsService_AddCoupon(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/14900541/add_coupon", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		if got, want := r.URL.Query().Get("code"), "SAVE 10%"; got != want {
+			t.Errorf("Subscriptions.AddCoupon code is %q, want %q", got, want)
+		}
+		fmt.Fprint(w, testSubJSON("active"))
+	})
+
+	if _, _, err := client.Subscriptions.AddCoupon(context.Background(), 14900541, "SAVE 10%"); err != nil {
+		t.Errorf("Subscriptions.AddCoupon returned error: %v", err)
+	}
+}
+
+func TestSubscriptionsService_Update(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/14900541", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), `{"subscription":{"receives_invoice_emails":false,"net_terms":0,"reference":null,"cancel_at_end_of_period":false,"balance_in_cents":0}}`+"\n"; got != want {
+			t.Errorf("Request body = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, testSubJSON("active"))
+	})
+
+	update := &SubscriptionUpdate{
+		ReceivesInvoiceEmails: Set(false),
+		NetTerms:              Set(0),
+		Reference:             Null[string](),
+		CancelAtEndOfPeriod:   Set(false),
+		BalanceInCents:        Set(0),
+	}
+	sub, _, err := client.Subscriptions.Update(context.Background(), 14900541, update)
+	if err != nil {
+		t.Errorf("Subscriptions.Update returned error: %v", err)
+	}
+	if sub == nil || sub.Id != 14900541 {
+		t.Errorf("Subscriptions.Update returned %+v, want subscription 14900541", sub)
+	}
+}
@@ -0,0 +1,262 @@
+package chargify
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// TransactionKind is the type of a Transaction.
+type TransactionKind string
+
+// Transaction kinds recorded by Chargify.
+const (
+	TransactionCharge               TransactionKind = "charge"
+	TransactionPayment              TransactionKind = "payment"
+	TransactionRefund               TransactionKind = "refund"
+	TransactionAdjustment           TransactionKind = "adjustment"
+	TransactionCredit               TransactionKind = "credit"
+	TransactionPaymentAuthorization TransactionKind = "payment_authorization"
+	TransactionInfo                 TransactionKind = "info"
+)
+
+type TransactionWrapper struct {
+	Transaction *Transaction `json:"transaction"`
+}
+
+// Transaction is an entry in the ledger of a subscription. TransactionType
+// holds the kind of the transaction, while Kind further qualifies charges
+// (e.g. "baseline", "initial", "trial", "component_proration").
+type Transaction struct {
+	Id                     int             `json:"id,omitempty"`
+	TransactionType        TransactionKind `json:"transaction_type,omitempty"`
+	Kind                   string          `json:"kind,omitempty"`
+	AmountInCents          int             `json:"amount_in_cents,omitempty"`
+	CreatedAt              *FormattedTime  `json:"created_at,omitempty"`
+	StartingBalanceInCents int             `json:"starting_balance_in_cents,omitempty"`
+	EndingBalanceInCents   int             `json:"ending_balance_in_cents,omitempty"`
+	Memo                   string          `json:"memo,omitempty"`
+	SubscriptionId         int             `json:"subscription_id,omitempty"`
+	CustomerId             int             `json:"customer_id,omitempty"`
+	ProductId              int             `json:"product_id,omitempty"`
+	Success                bool            `json:"success,omitempty"`
+	PaymentId              int             `json:"payment_id,omitempty"`
+	GatewayTransactionId   string          `json:"gateway_transaction_id,omitempty"`
+	GatewayOrderId         string          `json:"gateway_order_id,omitempty"`
+	StatementId            int             `json:"statement_id,omitempty"`
+	InvoiceUid             string          `json:"invoice_uid,omitempty"`
+	CardNumber             string          `json:"card_number,omitempty"`
+	CardExpiration         string          `json:"card_expiration,omitempty"`
+	CardType               string          `json:"card_type,omitempty"`
+	RefundedAmountInCents  int             `json:"refunded_amount_in_cents,omitempty"`
+	OriginalAmountInCents  int             `json:"original_amount_in_cents,omitempty"`
+
+	// Extra holds the fields of the API response with no field above.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// TransactionListOptions specifies the optional parameters to the
+// TransactionsService.List and TransactionsService.ListForSubscription
+// methods.
+type TransactionListOptions struct {
+	// Kinds limits the results to transactions of the given kinds.
+	Kinds []TransactionKind `url:"kinds[],omitempty"`
+
+	// SinceID returns only transactions with an id greater than or equal
+	// to it.
+	SinceID int `url:"since_id,omitempty"`
+
+	// MaxID returns only transactions with an id less than or equal to it.
+	MaxID int `url:"max_id,omitempty"`
+
+	// SinceDate and UntilDate restrict the results to transactions created
+	// on or between the given days.
+	SinceDate time.Time `url:"since_date,omitempty" layout:"2006-01-02"`
+	UntilDate time.Time `url:"until_date,omitempty" layout:"2006-01-02"`
+
+	// Direction sorts the transactions by id. Can be one of "asc" or
+	// "desc". Default: "desc".
+	Direction string `url:"direction,omitempty"`
+
+	ListOptions
+}
+
+type TransactionsService service
+
+// List fetches the transactions for the site.
+//
+// Chargify API docs: https://reference.chargify.com/v1/transactions/list-transactions
+func (s *TransactionsService) List(ctx context.Context, opt *TransactionListOptions) ([]*Transaction, *Response, error) {
+	ctx = withOperationName(ctx, "Transactions.List")
+	return s.list(ctx, "transactions", opt)
+}
+
+// ListForSubscription fetches the transactions for a subscription.
+//
+// Chargify API docs: https://reference.chargify.com/v1/transactions/list-transactions-for-subscription
+func (s *TransactionsService) ListForSubscription(ctx context.Context, subscriptionID int, opt *TransactionListOptions) ([]*Transaction, *Response, error) {
+	ctx = withOperationName(ctx, "Transactions.ListForSubscription")
+	return s.list(ctx, fmt.Sprintf("subscriptions/%d/transactions", subscriptionID), opt)
+}
+
+func (s *TransactionsService) list(ctx context.Context, u string, opt *TransactionListOptions) ([]*Transaction, *Response, error) {
+	u, err := addOptions(u, opt)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var wrappers []*TransactionWrapper
+	resp, err := s.client.Do(ctx, req, &wrappers)
+	if err != nil {
+		return nil, resp, err
+	}
+	var transactions []*Transaction
+	for _, t := range wrappers {
+		transactions = append(transactions, t.Transaction)
+	}
+	if opt != nil {
+		resp.setPageValues(&opt.ListOptions, len(transactions))
+	} else {
+		resp.setPageValues(nil, len(transactions))
+	}
+	return transactions, resp, nil
+}
+
+// TransactionIterator walks every page of a transaction listing, fetching
+// pages on demand.
+//
+//	it := client.Transactions.Iter(opt)
+//	for it.Next(ctx) {
+//		t := it.Transaction()
+//		// ...
+//	}
+//	if err := it.Err(); err != nil {
+//		// ...
+//	}
+type TransactionIterator struct {
+	svc            *TransactionsService
+	subscriptionID int
+	opt            TransactionListOptions
+
+	page []*Transaction
+	cur  *Transaction
+	done bool
+	err  error
+}
+
+// Iter returns an iterator over the transactions for the site matching opt.
+func (s *TransactionsService) Iter(opt *TransactionListOptions) *TransactionIterator {
+	return s.IterForSubscription(0, opt)
+}
+
+// IterForSubscription returns an iterator over the transactions for a
+// subscription matching opt.
+func (s *TransactionsService) IterForSubscription(subscriptionID int, opt *TransactionListOptions) *TransactionIterator {
+	it := &TransactionIterator{svc: s, subscriptionID: subscriptionID}
+	if opt != nil {
+		it.opt = *opt
+	}
+	if it.opt.Page == 0 {
+		it.opt.Page = 1
+	}
+	return it
+}
+
+// Next advances the iterator to the next transaction, fetching the next page
+// if needed. It returns false when there are no more transactions or an error
+// occurred.
+func (it *TransactionIterator) Next(ctx context.Context) bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		var resp *Response
+		if it.subscriptionID != 0 {
+			it.page, resp, it.err = it.svc.ListForSubscription(ctx, it.subscriptionID, &it.opt)
+		} else {
+			it.page, resp, it.err = it.svc.List(ctx, &it.opt)
+		}
+		if it.err != nil {
+			return false
+		}
+		if resp.NextPage == 0 {
+			it.done = true
+		}
+		it.opt.Page = resp.NextPage
+	}
+	it.cur, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Transaction returns the current transaction.
+func (it *TransactionIterator) Transaction() *Transaction {
+	return it.cur
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *TransactionIterator) Err() error {
+	return it.err
+}
+
+var transactionCSVHeader = []string{
+	"id", "created_at", "transaction_type", "kind", "amount_in_cents", "memo",
+	"subscription_id", "customer_id", "product_id", "success", "payment_id",
+	"gateway_transaction_id",
+}
+
+// ExportCSV writes the transactions for the site created between start and
+// end, inclusive of both days, to w as CSV with a header row. Transactions
+// are written in ascending id order.
+func (s *TransactionsService) ExportCSV(ctx context.Context, w io.Writer, start, end time.Time) error {
+	ctx = withOperationName(ctx, "Transactions.ExportCSV")
+
+	opt := &TransactionListOptions{
+		SinceDate:   start,
+		UntilDate:   end,
+		Direction:   "asc",
+		ListOptions: ListOptions{PerPage: maxPerPage},
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(transactionCSVHeader); err != nil {
+		return err
+	}
+	it := s.Iter(opt)
+	for it.Next(ctx) {
+		t := it.Transaction()
+		var createdAt string
+		if t.CreatedAt != nil && t.CreatedAt.Time != nil {
+			createdAt = t.CreatedAt.Format(time.RFC3339)
+		}
+		record := []string{
+			strconv.Itoa(t.Id),
+			createdAt,
+			string(t.TransactionType),
+			t.Kind,
+			strconv.Itoa(t.AmountInCents),
+			t.Memo,
+			strconv.Itoa(t.SubscriptionId),
+			strconv.Itoa(t.CustomerId),
+			strconv.Itoa(t.ProductId),
+			strconv.FormatBool(t.Success),
+			strconv.Itoa(t.PaymentId),
+			t.GatewayTransactionId,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
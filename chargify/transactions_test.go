@@ -0,0 +1,119 @@
+package chargify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTransactionsService_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/transactions", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		want := "kinds%5B%5D=charge&kinds%5B%5D=refund&max_id=50"
+		if got := r.URL.RawQuery; got != want {
+			t.Errorf("Transactions.List query is %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `[{"transaction": {"id":1,"transaction_type":"charge"}},{"transaction": {"id":2,"transaction_type":"refund"}}]`)
+	})
+
+	opt := &TransactionListOptions{Kinds: []TransactionKind{TransactionCharge, TransactionRefund}, MaxID: 50}
+	transactions, _, err := client.Transactions.List(context.Background(), opt)
+	if err != nil {
+		t.Errorf("Transactions.List returned error: %v", err)
+	}
+
+	want := []*Transaction{{Id: 1, TransactionType: TransactionCharge}, {Id: 2, TransactionType: TransactionRefund}}
+	if !reflect.DeepEqual(transactions, want) {
+		t.Errorf("Transactions.List returned %+v, want %+v", transactions, want)
+	}
+}
+
+func TestTransactionsService_ListForSubscription(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions/14900541/transactions", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `[{"transaction": {"id":1,"subscription_id":14900541}}]`)
+	})
+
+	transactions, _, err := client.Transactions.ListForSubscription(context.Background(), 14900541, nil)
+	if err != nil {
+		t.Errorf("Transactions.ListForSubscription returned error: %v", err)
+	}
+
+	want := []*Transaction{{Id: 1, SubscriptionId: 14900541}}
+	if !reflect.DeepEqual(transactions, want) {
+		t.Errorf("Transactions.ListForSubscription returned %+v, want %+v", transactions, want)
+	}
+}
+
+func TestTransactionIterator(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/transactions", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "1":
+			fmt.Fprint(w, `[{"transaction": {"id":1}},{"transaction": {"id":2}}]`)
+		case "2":
+			fmt.Fprint(w, `[{"transaction": {"id":3}}]`)
+		default:
+			t.Errorf("Unexpected page %q requested", r.URL.Query().Get("page"))
+			fmt.Fprint(w, `[]`)
+		}
+	})
+
+	it := client.Transactions.Iter(&TransactionListOptions{ListOptions: ListOptions{PerPage: 2}})
+	var ids []int
+	for it.Next(context.Background()) {
+		ids = append(ids, it.Transaction().Id)
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("TransactionIterator returned error: %v", err)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("TransactionIterator returned %v, want %v", ids, want)
+	}
+}
+
+func TestTransactionsService_ExportCSV(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/transactions", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("since_date") != "2016-11-01" || q.Get("until_date") != "2016-11-30" || q.Get("direction") != "asc" {
+			t.Errorf("Transactions.ExportCSV query is %v", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `[{"transaction": {
+			"id": 7,
+			"created_at": "2016-11-03T09:34:37-04:00",
+			"transaction_type": "payment",
+			"amount_in_cents": 4000,
+			"memo": "Payment, thanks",
+			"subscription_id": 14900541,
+			"success": true
+		}}]`)
+	})
+
+	var buf bytes.Buffer
+	start := time.Date(2016, 11, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2016, 11, 30, 0, 0, 0, 0, time.UTC)
+	if err := client.Transactions.ExportCSV(context.Background(), &buf, start, end); err != nil {
+		t.Fatalf("Transactions.ExportCSV returned error: %v", err)
+	}
+
+	want := "id,created_at,transaction_type,kind,amount_in_cents,memo,subscription_id,customer_id,product_id,success,payment_id,gateway_transaction_id\n" +
+		"7,2016-11-03T09:34:37-04:00,payment,,4000,\"Payment, thanks\",14900541,0,0,true,0,\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Transactions.ExportCSV wrote %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,10 @@
+package tests
+
+import "testing"
+
+func TestListEvents(t *testing.T) {
+	_, _, err := client.Events.List(ctx, nil)
+	if err != nil {
+		t.Error(err)
+	}
+}
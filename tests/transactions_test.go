@@ -0,0 +1,10 @@
+package tests
+
+import "testing"
+
+func TestListTransactions(t *testing.T) {
+	_, _, err := client.Transactions.List(ctx, nil)
+	if err != nil {
+		t.Error(err)
+	}
+}